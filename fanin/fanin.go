@@ -0,0 +1,253 @@
+package fanin
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type Option func(*config)
+
+// WithContext makes the merge stop once ctx is cancelled: the output channel is closed and the remaining items of the sources are abandoned.
+func WithContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.ctx = ctx
+	}
+}
+
+// WithFairness makes the merge take items from the sources in turn whenever several of them have items ready,
+// so that a busy source cannot starve the others.
+//
+// By default, every source is forwarded by its own goroutine, and the order in which ready sources are served is up to the Go runtime.
+func WithFairness() Option {
+	return func(c *config) {
+		c.fair = true
+	}
+}
+
+// config holds the settings of a merge, as set by its options.
+type config struct {
+	ctx  context.Context
+	fair bool
+}
+
+// Merge combines the items of all given channels into a single channel, which is closed once all of them are closed.
+//
+// Items from the same channel keep their order, while items from different channels are interleaved.
+// Use NewMerger() to pass options, or to add channels while merging.
+func Merge[T any](chans ...<-chan T) <-chan T {
+	m := NewMerger[T]()
+	for _, ch := range chans {
+		m.Add(ch)
+	}
+	m.Close()
+
+	return m.Out()
+}
+
+// Merger combines the items of a dynamic set of channels into a single channel.
+//
+// A new merger must be created using NewMerger(). Channels can be added using Add() while the merger is running.
+// Once Close() has been called, the output channel is closed as soon as all added channels are closed.
+type Merger[T any] struct {
+	ctx  context.Context
+	fair bool
+
+	out chan T
+
+	mu      sync.Mutex
+	closed  bool
+	wg      sync.WaitGroup
+	pending []<-chan T
+	notify  chan struct{}
+}
+
+// NewMerger creates a new Merger and starts merging.
+func NewMerger[T any](opts ...Option) *Merger[T] {
+	c := &config{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	m := &Merger[T]{
+		ctx:    c.ctx,
+		fair:   c.fair,
+		out:    make(chan T),
+		notify: make(chan struct{}, 1),
+	}
+
+	if m.fair {
+		go m.forward()
+	} else {
+		// the merger itself holds the wait group open until Close() is called, or until the context is cancelled
+		m.wg.Add(1)
+		stop := context.AfterFunc(m.ctx, m.Close)
+		go func() {
+			m.wg.Wait()
+			stop()
+			close(m.out)
+		}()
+	}
+
+	return m
+}
+
+// Add adds a channel to merge, and reports whether it was added.
+//
+// A channel added after Close() has been called, or after the context of the merger has been cancelled, is not added.
+func (m *Merger[T]) Add(ch <-chan T) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed || m.ctx.Err() != nil {
+		return false
+	}
+
+	if m.fair {
+		m.pending = append(m.pending, ch)
+		m.wake()
+		return true
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case item, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case m.out <- item:
+				case <-m.ctx.Done():
+					return
+				}
+			case <-m.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return true
+}
+
+// Close marks the set of channels as complete. The output channel is closed once all added channels are closed.
+//
+// Calling Close() more than once has no effect.
+func (m *Merger[T]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.closed = true
+
+	if m.fair {
+		m.wake()
+	} else {
+		m.wg.Done()
+	}
+}
+
+// Out returns the output channel of the merger.
+func (m *Merger[T]) Out() <-chan T {
+	return m.out
+}
+
+// wake notifies the fair forwarder of new channels or of Close(). It must be called with m.mu held.
+func (m *Merger[T]) wake() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// forward merges the channels in a single goroutine, taking items from the ready channels in turn.
+//
+// It first polls the channels without blocking, starting after the one served last; if none of them is ready,
+// it blocks until any of them is, or until a channel is added, the merger is closed, or the context is cancelled.
+func (m *Merger[T]) forward() {
+	defer close(m.out)
+
+	var sources []<-chan T
+	next := 0
+
+	for {
+		m.mu.Lock()
+		sources = append(sources, m.pending...)
+		m.pending = nil
+		closed := m.closed
+		m.mu.Unlock()
+
+		if closed && len(sources) == 0 {
+			return
+		}
+
+		i, item, ok := m.poll(sources, next)
+		if i < 0 {
+			i, item, ok = m.wait(sources)
+			if i < 0 {
+				if m.ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+		}
+
+		if !ok {
+			sources = append(sources[:i], sources[i+1:]...)
+			next = i
+			continue
+		}
+
+		select {
+		case m.out <- item:
+		case <-m.ctx.Done():
+			return
+		}
+		next = i + 1
+	}
+}
+
+// poll tries to receive from the sources without blocking, starting at index next, and returns the index of the source
+// it received from, or -1 if none of them is ready.
+func (m *Merger[T]) poll(sources []<-chan T, next int) (int, T, bool) {
+	var zero T
+	for k := range sources {
+		i := (next + k) % len(sources)
+		select {
+		case item, ok := <-sources[i]:
+			return i, item, ok
+		default:
+		}
+	}
+	return -1, zero, false
+}
+
+// wait blocks until any of the sources is ready and returns the index of the source it received from,
+// or -1 if it was woken up by Add(), Close() or the context instead.
+func (m *Merger[T]) wait(sources []<-chan T) (int, T, bool) {
+	var zero T
+
+	cases := make([]reflect.SelectCase, 0, len(sources)+2)
+	for _, ch := range sources {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+	}
+	cases = append(cases,
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(m.notify)},
+		reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(m.ctx.Done())},
+	)
+
+	i, v, ok := reflect.Select(cases)
+	if i >= len(sources) {
+		return -1, zero, false
+	}
+	if !ok {
+		return i, zero, false
+	}
+	// a nil item of an interface type T arrives as a nil interface, which a plain type assertion would panic on
+	item, _ := v.Interface().(T)
+	return i, item, true
+}
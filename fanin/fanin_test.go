@@ -0,0 +1,230 @@
+package fanin_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/fanin"
+	"github.com/kiriyms/conpats/tee"
+)
+
+var basicCases = []struct {
+	name  string
+	chans int
+	work  int
+}{
+	{"basic", 3, 100},
+	{"single channel", 1, 100},
+	{"many channels", 20, 50},
+	{"no channels", 0, 0},
+	{"no work", 3, 0},
+}
+
+// source returns a channel that emits the items from..from+n-1 and is then closed.
+func source(from, n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range n {
+			ch <- from + i
+		}
+	}()
+	return ch
+}
+
+// sources returns n channels, each emitting work distinct items.
+func sources(n, work int) []<-chan int {
+	chans := make([]<-chan int, n)
+	for i := range n {
+		chans[i] = source(i*work, work)
+	}
+	return chans
+}
+
+// checkMerged verifies that results hold all items of n sources of work items each,
+// with the items of every source in order.
+func checkMerged(t *testing.T, results []int, n, work int) {
+	t.Helper()
+
+	if len(results) != n*work {
+		t.Fatalf("expected %d items, got %d", n*work, len(results))
+	}
+
+	last := make([]int, n)
+	for i := range last {
+		last[i] = -1
+	}
+	for _, v := range results {
+		src := v / work
+		if v <= last[src] {
+			t.Fatalf("expected items of source %d in order, got %d after %d", src, v, last[src])
+		}
+		last[src] = v
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range basicCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var results []int
+			for v := range fanin.Merge(sources(tc.chans, tc.work)...) {
+				results = append(results, v)
+			}
+
+			checkMerged(t, results, tc.chans, tc.work)
+		})
+	}
+
+	t.Run("recombines tee'd branches", func(t *testing.T) {
+		t.Parallel()
+
+		outs := tee.NewTee(source(0, 50), 2, 0)
+
+		var results []int
+		for v := range fanin.Merge[int](outs[0], outs[1]) {
+			results = append(results, v)
+		}
+
+		if len(results) != 100 {
+			t.Fatalf("expected 100 items, got %d", len(results))
+		}
+		slices.Sort(results)
+		for i, v := range results {
+			if v != i/2 {
+				t.Fatalf("expected every item twice, got %v", results)
+			}
+		}
+	})
+}
+
+func TestMerger(t *testing.T) {
+	t.Parallel()
+
+	for _, fair := range []bool{false, true} {
+		var opts []fanin.Option
+		name := "default"
+		if fair {
+			opts = append(opts, fanin.WithFairness())
+			name = "fair"
+		}
+
+		t.Run(name+" merges channels added while running", func(t *testing.T) {
+			t.Parallel()
+
+			m := fanin.NewMerger[int](opts...)
+			done := make(chan []int)
+			go func() {
+				var results []int
+				for v := range m.Out() {
+					results = append(results, v)
+				}
+				done <- results
+			}()
+
+			for i := range 5 {
+				if !m.Add(source(i*20, 20)) {
+					t.Fatalf("expected channel %d to be added", i)
+				}
+				time.Sleep(time.Millisecond)
+			}
+			m.Close()
+
+			if m.Add(source(0, 1)) {
+				t.Errorf("expected Add to fail after Close")
+			}
+
+			checkMerged(t, <-done, 5, 20)
+		})
+
+		t.Run(name+" closes output on cancellation", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			m := fanin.NewMerger[int](append(opts, fanin.WithContext(ctx))...)
+
+			never := make(chan int)
+			m.Add(never)
+			m.Add(source(0, 3))
+
+			for range 3 {
+				<-m.Out()
+			}
+			cancel()
+
+			select {
+			case _, ok := <-m.Out():
+				if ok {
+					t.Errorf("expected no more items after cancellation")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected output to be closed after cancellation")
+			}
+
+			if m.Add(source(0, 1)) {
+				t.Errorf("expected Add to fail after cancellation")
+			}
+		})
+	}
+
+	t.Run("fair merger takes ready channels in turn", func(t *testing.T) {
+		t.Parallel()
+
+		busy := make(chan int, 10)
+		quiet := make(chan int, 10)
+		for i := range 10 {
+			busy <- 0
+			if i < 3 {
+				quiet <- 1
+			}
+		}
+		close(busy)
+		close(quiet)
+
+		m := fanin.NewMerger[int](fanin.WithFairness())
+		m.Add(busy)
+		m.Add(quiet)
+		m.Close()
+
+		var results []int
+		for v := range m.Out() {
+			results = append(results, v)
+		}
+
+		expected := []int{0, 1, 0, 1, 0, 1, 0, 0, 0, 0, 0, 0, 0}
+		if !slices.Equal(results, expected) {
+			t.Errorf("expected %v, got %v", expected, results)
+		}
+	})
+}
+
+func TestMergerNilItems(t *testing.T) {
+	t.Parallel()
+
+	m := fanin.NewMerger[error](fanin.WithFairness())
+
+	src := make(chan error)
+	m.Add(src)
+	m.Close()
+
+	go func() {
+		defer close(src)
+		// give the forwarder time to block in wait(), so that the item is received there rather than by poll()
+		time.Sleep(20 * time.Millisecond)
+		src <- nil
+	}()
+
+	var results []error
+	for err := range m.Out() {
+		results = append(results, err)
+	}
+
+	if len(results) != 1 || results[0] != nil {
+		t.Errorf("expected a single nil item, got %v", results)
+	}
+}
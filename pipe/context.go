@@ -0,0 +1,155 @@
+package pipe
+
+import (
+	"context"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+// PipeFromChanCtx creates a pipe like PipeFromChanErr(), using a function that receives a context.
+//
+// The pipe runs on a pool.ContextPool, whose context is derived from ctx and passed to fn.
+// Once ctx is cancelled, the pipe stops reading the input channel, abandoning the remaining items, unblocks all pending sends,
+// and closes the output channel once the items being processed return. The error of ctx is then recorded as well,
+// unless the policy is RouteErrors. Errors returned by fn after cancellation are not recorded.
+//
+// With StopOnError, the first error cancels the context passed to the other items. WithPool() has no effect on these pipes.
+func PipeFromChanCtx[I, O any](ctx context.Context, fn func(context.Context, I) (O, error), in <-chan I, workers int, opts ...Option) (<-chan O, *Errors) {
+	return runCtx(ctx, fn, in, newConfig(workers, opts))
+}
+
+// PipeFromSliceCtx creates a pipe like PipeFromSliceErr(), using a function that receives a context.
+//
+// See PipeFromChanCtx() for how cancellation of ctx is handled. The generator goroutine feeding the slice stops once ctx is cancelled.
+func PipeFromSliceCtx[I, O any](ctx context.Context, fn func(context.Context, I) (O, error), items []I, workers int, opts ...Option) (<-chan O, *Errors) {
+	c := newConfig(workers, opts)
+
+	in := make(chan I)
+
+	go func() {
+		defer close(in)
+		for _, item := range items {
+			select {
+			case in <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return runCtx(ctx, fn, in, c)
+}
+
+// runCtx processes the items on a pool.ContextPool, handling errors according to the policy of the config,
+// and abandoning the remaining work once ctx is cancelled.
+func runCtx[I, O any](parent context.Context, fn func(context.Context, I) (O, error), in <-chan I, c *config) (<-chan O, *Errors) {
+	ctx, cancel := context.WithCancel(parent)
+	errs := newErrors(c.policy)
+	errs.cancelled = ctx.Done()
+	p := pool.New(c.workers).WithErrors().WithContext(ctx)
+	out := make(chan O)
+
+	job := func(ctx context.Context, item I) (O, bool) {
+		c.limit(ctx)
+		v, err := fn(ctx, item)
+		if err != nil {
+			if ctx.Err() == nil {
+				errs.add(err)
+				if errs.isStopped() {
+					cancel()
+				}
+			}
+			return v, false
+		}
+		return v, ctx.Err() == nil
+	}
+
+	send := func(v O) {
+		select {
+		case out <- v:
+		case <-ctx.Done():
+		}
+	}
+
+	var slots chan chan result[O]
+	if c.ordered {
+		// the slot being emitted has already left the channel, so it only buffers the other window-1 slots
+		slots = make(chan chan result[O], c.window-1)
+	}
+
+	finish := func() {
+		// jobs only return errors when they panic
+		for _, err := range p.Wait() {
+			errs.add(err)
+		}
+		if err := parent.Err(); err != nil && c.policy != RouteErrors {
+			errs.add(err)
+		}
+		cancel()
+		errs.finish()
+		close(out)
+	}
+
+	go func() {
+		if slots != nil {
+			defer close(slots)
+		} else {
+			defer finish()
+		}
+
+		for {
+			var item I
+			var ok bool
+			select {
+			case item, ok = <-in:
+			case <-ctx.Done():
+			}
+			if !ok {
+				return
+			}
+
+			if slots == nil {
+				err := p.GoContext(ctx, func(ctx context.Context) error {
+					if v, ok := job(ctx, item); ok {
+						send(v)
+					}
+					return nil
+				})
+				if err != nil {
+					return
+				}
+				continue
+			}
+
+			slot := make(chan result[O], 1)
+			select {
+			case slots <- slot:
+			case <-ctx.Done():
+				return
+			}
+			err := p.GoContext(ctx, func(ctx context.Context) error {
+				var r result[O]
+				defer func() { slot <- r }()
+				r.value, r.ok = job(ctx, item)
+				return nil
+			})
+			if err != nil {
+				slot <- result[O]{}
+				return
+			}
+		}
+	}()
+
+	if slots != nil {
+		go func() {
+			defer finish()
+			for slot := range slots {
+				if r := <-slot; r.ok {
+					send(r.value)
+				}
+			}
+		}()
+	}
+
+	return out, errs
+}
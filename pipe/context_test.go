@@ -0,0 +1,204 @@
+package pipe_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pipe"
+	"github.com/kiriyms/conpats/ratelimit"
+)
+
+func TestPipeCtx(t *testing.T) {
+	t.Parallel()
+
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	t.Run("processes all items", func(t *testing.T) {
+		t.Parallel()
+
+		out, errs := pipe.PipeFromSliceCtx(context.Background(), func(ctx context.Context, x int) (int, error) {
+			return x * 2, nil
+		}, items, 4)
+		results := pipe.Collect(out)
+		sort.Ints(results)
+
+		if len(results) != 20 {
+			t.Fatalf("expected 20 results, got %d", len(results))
+		}
+		for i, v := range results {
+			if v != i*2 {
+				t.Errorf("expected %d, got %d", i*2, v)
+			}
+		}
+		if err := errs.Err(); err != nil {
+			t.Errorf("expected no errors, got %v", err)
+		}
+	})
+
+	t.Run("closes output when consumer stops reading and ctx is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case in <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		out, errs := pipe.PipeFromChanCtx(ctx, func(ctx context.Context, x int) (int, error) {
+			return x, nil
+		}, in, 4)
+
+		<-out
+		<-out
+		cancel()
+
+		done := make(chan struct{})
+		go func() {
+			for range out {
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected output to be closed after cancellation")
+		}
+		if err := errs.Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("unblocks routed errors on cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		out, errs := pipe.PipeFromSliceCtx(ctx, func(ctx context.Context, x int) (int, error) {
+			return 0, errors.New("test")
+		}, items, 4, pipe.WithErrorPolicy(pipe.RouteErrors))
+
+		// nobody reads errs.Chan(), so the workers block on sending their errors until ctx is cancelled
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		done := make(chan struct{})
+		go func() {
+			for range out {
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected output to be closed after cancellation")
+		}
+		if errs.Wait() != nil {
+			t.Errorf("expected no recorded errors with RouteErrors")
+		}
+	})
+
+	t.Run("stop on error cancels other items", func(t *testing.T) {
+		t.Parallel()
+
+		errTest := errors.New("test")
+		out, errs := pipe.PipeFromSliceCtx(context.Background(), func(ctx context.Context, x int) (int, error) {
+			if x == 3 {
+				return 0, errTest
+			}
+			if x > 3 {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+			return x, nil
+		}, items, 4, pipe.WithErrorPolicy(pipe.StopOnError))
+		pipe.Collect(out)
+
+		recorded := errs.Wait()
+		if len(recorded) != 1 || !errors.Is(recorded[0], errTest) {
+			t.Errorf("expected only %v, got %v", errTest, recorded)
+		}
+	})
+
+	t.Run("keeps input order with ordered output", func(t *testing.T) {
+		t.Parallel()
+
+		out, _ := pipe.PipeFromSliceCtx(context.Background(), func(ctx context.Context, x int) (int, error) {
+			time.Sleep(time.Duration(x%5) * 100 * time.Microsecond)
+			return x, nil
+		}, items, 4, pipe.WithOrderedOutput(6))
+		results := pipe.Collect(out)
+
+		if len(results) != 20 {
+			t.Fatalf("expected 20 results, got %d", len(results))
+		}
+		for i, v := range results {
+			if v != i {
+				t.Errorf("expected %d at index %d, got %d", i, i, v)
+			}
+		}
+	})
+
+	t.Run("keeps waiting for the rate limiter before the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		start := time.Now()
+
+		out, errs := pipe.PipeFromSliceCtx(ctx, func(ctx context.Context, x int) (int, error) {
+			return x, nil
+		}, []int{1, 2, 3}, 3, pipe.WithLimiter(ratelimit.New(50, 1)))
+		results := pipe.Collect(out)
+
+		if len(results) != 3 {
+			t.Fatalf("expected 3 results, got %d", len(results))
+		}
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("expected rate limited pipe to take at least 30ms, took %v", elapsed)
+		}
+		if err := errs.Err(); err != nil {
+			t.Errorf("expected no errors, got %v", err)
+		}
+	})
+}
+
+// TestPipeCtxLeak is not parallel, so that no other test changes the number of goroutines while it runs.
+func TestPipeCtxLeak(t *testing.T) {
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	before := runtime.NumGoroutine()
+
+	out, errs := pipe.PipeFromSliceCtx(ctx, func(ctx context.Context, x int) (int, error) {
+		return x, nil
+	}, items, 4, pipe.WithOrderedOutput(4))
+
+	<-out
+	cancel()
+	errs.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected no leaked goroutines, got %d more", n-before)
+	}
+}
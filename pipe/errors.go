@@ -0,0 +1,185 @@
+package pipe
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+// ErrorPolicy selects what an error-aware Pipe does with the errors returned by its function.
+type ErrorPolicy int
+
+const (
+	// DropErrors drops the items that failed and records their errors, which are returned by Errors.Wait(). This is the default.
+	DropErrors ErrorPolicy = iota
+	// StopOnError stops the Pipe on the first error: the remaining input items are discarded, no more results are emitted,
+	// and the output channel is closed once the items being processed finish, without waiting for the input channel to be closed.
+	// Only the first error is recorded.
+	StopOnError
+	// RouteErrors sends the errors to the channel returned by Errors.Chan() instead of recording them.
+	// The channel must be read concurrently with the output channel, otherwise the Pipe blocks.
+	// Pipes with a context drop the errors that are not read before the context is cancelled.
+	RouteErrors
+)
+
+// WithErrorPolicy sets what an error-aware Pipe, created using PipeFromChanErr() or PipeFromSliceErr(), does with errors.
+// By default, DropErrors is used. The option has no effect on other Pipes.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(c *config) {
+		c.policy = policy
+	}
+}
+
+// Errors gives access to the errors of an error-aware Pipe, created using PipeFromChanErr() or PipeFromSliceErr().
+type Errors struct {
+	policy ErrorPolicy
+
+	mu   sync.Mutex
+	errs []error
+
+	ch chan error
+	// cancelled, if not nil, is closed once the context of the Pipe is cancelled, after which routed errors are dropped.
+	cancelled <-chan struct{}
+	stopped   chan struct{}
+	stop      sync.Once
+	done      chan struct{}
+}
+
+func newErrors(policy ErrorPolicy) *Errors {
+	e := &Errors{
+		policy:  policy,
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if policy == RouteErrors {
+		e.ch = make(chan error)
+	}
+	return e
+}
+
+// Wait blocks until the Pipe is finished and returns the recorded errors. The Pipe is finished before its output channel is closed,
+// so Wait does not block once the output channel has been drained.
+//
+// With RouteErrors, no errors are recorded and Wait returns nil.
+func (e *Errors) Wait() []error {
+	<-e.done
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.errs
+}
+
+// Err blocks until the Pipe is finished and returns the recorded errors joined using errors.Join(), or nil if there are none.
+func (e *Errors) Err() error {
+	return errors.Join(e.Wait()...)
+}
+
+// Chan returns the channel the errors are sent to with RouteErrors, which is closed once the Pipe is finished.
+// With other policies, Chan returns nil.
+func (e *Errors) Chan() <-chan error {
+	return e.ch
+}
+
+// add handles the error of an item according to the policy.
+func (e *Errors) add(err error) {
+	switch e.policy {
+	case RouteErrors:
+		select {
+		case e.ch <- err:
+		case <-e.cancelled:
+		}
+	case StopOnError:
+		e.stop.Do(func() {
+			e.mu.Lock()
+			e.errs = append(e.errs, err)
+			e.mu.Unlock()
+			close(e.stopped)
+		})
+	default:
+		e.mu.Lock()
+		e.errs = append(e.errs, err)
+		e.mu.Unlock()
+	}
+}
+
+// isStopped reports whether the Pipe has been stopped by an error.
+func (e *Errors) isStopped() bool {
+	select {
+	case <-e.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish marks the Pipe as finished. It must be called once all items have been processed.
+func (e *Errors) finish() {
+	if e.ch != nil {
+		close(e.ch)
+	}
+	close(e.done)
+}
+
+// result is the outcome of an item processed by an error-aware Pipe.
+type result[O any] struct {
+	value O
+	ok    bool
+}
+
+// runErr processes the items like run, handling the errors returned by fn according to the policy of the config.
+func runErr[I, O any](fn func(I) (O, error), in <-chan I, c *config) (<-chan O, *Errors) {
+	errs := newErrors(c.policy)
+
+	// items is closed as soon as the pipe is stopped, so that the output channel does not wait for the end of the input.
+	// The rest of the input is then drained in the background, so that upstream stages are not blocked forever.
+	items := make(chan I)
+	go func() {
+		defer close(items)
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case items <- item:
+					continue
+				case <-errs.stopped:
+				}
+			case <-errs.stopped:
+			}
+
+			go func() {
+				for range in {
+				}
+			}()
+			return
+		}
+	}()
+
+	// panics are handled as errors, whatever the panic handler of the config
+	c.onPanic = func(pe *pool.PanicError) { errs.add(pe) }
+	results := run(func(item I) result[O] {
+		v, err := fn(item)
+		if err != nil {
+			errs.add(err)
+			return result[O]{}
+		}
+		return result[O]{value: v, ok: true}
+	}, items, c)
+
+	out := make(chan O)
+	go func() {
+		defer close(out)
+		defer errs.finish()
+		for r := range results {
+			if r.ok && !errs.isStopped() {
+				out <- r.value
+			}
+		}
+	}()
+
+	return out, errs
+}
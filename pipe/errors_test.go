@@ -0,0 +1,171 @@
+package pipe_test
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pipe"
+)
+
+func TestPipeErr(t *testing.T) {
+	t.Parallel()
+
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	failOdd := func(x int) (int, error) {
+		if x%2 == 1 {
+			return 0, fmt.Errorf("odd %d", x)
+		}
+		return x, nil
+	}
+
+	t.Run("drops and records errors", func(t *testing.T) {
+		t.Parallel()
+
+		out, errs := pipe.PipeFromSliceErr(failOdd, items, 4)
+		results := pipe.Collect(out)
+		sort.Ints(results)
+
+		if len(results) != 10 {
+			t.Fatalf("expected 10 results, got %d", len(results))
+		}
+		for i, v := range results {
+			if v != i*2 {
+				t.Errorf("expected %d, got %d", i*2, v)
+			}
+		}
+		if n := len(errs.Wait()); n != 10 {
+			t.Errorf("expected 10 errors, got %d", n)
+		}
+		if errs.Chan() != nil {
+			t.Error("expected nil error channel without RouteErrors")
+		}
+	})
+
+	t.Run("stops on first error", func(t *testing.T) {
+		t.Parallel()
+
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; i < 1000; i++ {
+				in <- i
+			}
+		}()
+
+		errTest := errors.New("test")
+		out, errs := pipe.PipeFromChanErr(func(x int) (int, error) {
+			if x == 5 {
+				return 0, errTest
+			}
+			return x, nil
+		}, in, 1, pipe.WithErrorPolicy(pipe.StopOnError))
+		results := pipe.Collect(out)
+
+		if len(results) > 5 {
+			t.Errorf("expected at most 5 results before the error, got %d", len(results))
+		}
+		for i, v := range results {
+			if v != i {
+				t.Errorf("expected %d, got %d", i, v)
+			}
+		}
+		if err := errs.Err(); !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+		if n := len(errs.Wait()); n != 1 {
+			t.Errorf("expected 1 error, got %d", n)
+		}
+	})
+
+	t.Run("closes output on error without waiting for the input", func(t *testing.T) {
+		t.Parallel()
+
+		stop := make(chan struct{})
+		defer close(stop)
+
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; ; i++ {
+				select {
+				case in <- i:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		errTest := errors.New("test")
+		out, errs := pipe.PipeFromChanErr(func(x int) (int, error) {
+			if x == 5 {
+				return 0, errTest
+			}
+			return x, nil
+		}, in, 2, pipe.WithErrorPolicy(pipe.StopOnError))
+
+		done := make(chan struct{})
+		go func() {
+			for range out {
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected output to be closed after the first error")
+		}
+		if err := errs.Err(); !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("routes errors to a channel", func(t *testing.T) {
+		t.Parallel()
+
+		out, errs := pipe.PipeFromSliceErr(failOdd, items, 4, pipe.WithErrorPolicy(pipe.RouteErrors))
+
+		routed := make(chan int)
+		go func() {
+			n := 0
+			for range errs.Chan() {
+				n++
+			}
+			routed <- n
+		}()
+
+		results := pipe.Collect(out)
+		if len(results) != 10 {
+			t.Errorf("expected 10 results, got %d", len(results))
+		}
+		if n := <-routed; n != 10 {
+			t.Errorf("expected 10 routed errors, got %d", n)
+		}
+		if errs.Wait() != nil {
+			t.Error("expected no recorded errors with RouteErrors")
+		}
+	})
+
+	t.Run("keeps input order with ordered output", func(t *testing.T) {
+		t.Parallel()
+
+		out, errs := pipe.PipeFromSliceErr(failOdd, items, 4, pipe.WithOrderedOutput(8))
+		results := pipe.Collect(out)
+
+		for i, v := range results {
+			if v != i*2 {
+				t.Errorf("expected %d at index %d, got %d", i*2, i, v)
+			}
+		}
+		if err := errs.Err(); err == nil {
+			t.Error("expected errors to be recorded")
+		}
+	})
+}
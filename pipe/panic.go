@@ -0,0 +1,40 @@
+package pipe
+
+import (
+	"runtime/debug"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+// WithPanicHandler makes the Pipe pass the first panic of its function to fn as a *pool.PanicError, instead of re-raising it.
+//
+// A panic in the function of a Pipe does not stop the other items from being processed. Once all items are processed,
+// fn is called before the output channel is closed, so the panic can be inspected once the channel has been drained.
+// By default, the panic is re-raised on the goroutine of the Pipe, which crashes the program.
+// Error-aware Pipes handle panics as errors instead, and ignore this option.
+func WithPanicHandler(fn func(*pool.PanicError)) Option {
+	return func(c *config) {
+		c.onPanic = fn
+	}
+}
+
+// wait waits for the pool and passes its panic, if any, to the panic handler of the config.
+// Without a panic handler, the panic is re-raised.
+func wait(p Pool, c *config) {
+	if c.onPanic == nil {
+		p.Wait()
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			pe, ok := r.(*pool.PanicError)
+			if !ok {
+				pe = &pool.PanicError{Value: r, Stack: debug.Stack()}
+			}
+			c.onPanic(pe)
+		}
+	}()
+
+	p.Wait()
+}
@@ -0,0 +1,111 @@
+package pipe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pipe"
+	cpool "github.com/kiriyms/conpats/pool"
+)
+
+func TestPipePanic(t *testing.T) {
+	t.Parallel()
+
+	boom := func(x int) int {
+		if x == 2 {
+			panic("boom")
+		}
+		return x
+	}
+
+	t.Run("panic handler receives the panic before the output is closed", func(t *testing.T) {
+		t.Parallel()
+
+		var pe *cpool.PanicError
+		out := pipe.PipeFromSlice(boom, []int{1, 2, 3, 4}, 2, pipe.WithPanicHandler(func(p *cpool.PanicError) {
+			pe = p
+		}))
+
+		if results := pipe.Collect(out); len(results) != 3 {
+			t.Errorf("expected 3 results, got %v", results)
+		}
+		if pe == nil || pe.Value != "boom" {
+			t.Fatalf("expected panic value 'boom', got %v", pe)
+		}
+	})
+
+	t.Run("panic handler is called once per pipe", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		out := pipe.PipeFromSlice(func(x int) int {
+			panic(x)
+		}, []int{1, 2, 3, 4}, 2, pipe.WithPanicHandler(func(*cpool.PanicError) {
+			calls++
+		}))
+
+		if results := pipe.Collect(out); len(results) != 0 {
+			t.Errorf("expected no results, got %v", results)
+		}
+		if calls != 1 {
+			t.Errorf("expected the panic handler to be called once, got %d", calls)
+		}
+	})
+
+	t.Run("ordered output does not hang", func(t *testing.T) {
+		t.Parallel()
+
+		var pe *cpool.PanicError
+		out := pipe.PipeFromSlice(boom, []int{1, 2, 3, 4}, 2, pipe.WithOrderedOutput(2), pipe.WithPanicHandler(func(p *cpool.PanicError) {
+			pe = p
+		}))
+
+		done := make(chan []int)
+		go func() {
+			var results []int
+			for v := range out {
+				results = append(results, v)
+			}
+			done <- results
+		}()
+
+		select {
+		case results := <-done:
+			expected := []int{1, 3, 4}
+			if len(results) != len(expected) {
+				t.Fatalf("expected %v, got %v", expected, results)
+			}
+			for i, v := range expected {
+				if results[i] != v {
+					t.Errorf("expected %v, got %v", expected, results)
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected output channel to be closed")
+		}
+
+		if pe == nil {
+			t.Errorf("expected panic to be handled")
+		}
+	})
+
+	t.Run("error-aware pipes record panics as errors", func(t *testing.T) {
+		t.Parallel()
+
+		out, errs := pipe.PipeFromSliceErr(func(x int) (int, error) {
+			return boom(x), nil
+		}, []int{1, 2, 3, 4}, 2)
+
+		if results := pipe.Collect(out); len(results) != 3 {
+			t.Errorf("expected 3 results, got %v", results)
+		}
+
+		got := errs.Wait()
+		if len(got) != 1 {
+			t.Fatalf("expected 1 error, got %v", got)
+		}
+		if _, ok := got[0].(*cpool.PanicError); !ok {
+			t.Errorf("expected *pool.PanicError, got %v", got[0])
+		}
+	})
+}
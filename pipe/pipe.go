@@ -1,6 +1,11 @@
 package pipe
 
-import "github.com/kiriyms/conpats/pool"
+import (
+	"context"
+
+	"github.com/kiriyms/conpats/pool"
+	"github.com/kiriyms/conpats/ratelimit"
+)
 
 // Pool defines the interface for a worker pool that the Pipe uses for concurrent processing.
 //
@@ -10,50 +15,143 @@ type Pool interface {
 	Wait()
 }
 
-type Option func(*Pool)
+type Option func(*config)
 
 // WithPool allows specifying a custom Pool implementation for the Pipe to use.
 func WithPool(p Pool) Option {
-	return func(pool *Pool) {
-		*pool = p
+	return func(c *config) {
+		c.pool = p
+	}
+}
+
+// WithRateLimit makes the Pipe wait for a token of a token bucket rate limiter before processing each item,
+// so that at most rate items per second are processed on average, with bursts of up to burst items.
+//
+// A rate of 0 or less leaves the Pipe without a rate limit. By default, items are processed as soon as a worker picks them up.
+func WithRateLimit(rate float64, burst int) Option {
+	if rate <= 0 {
+		return func(*config) {}
+	}
+	return WithLimiter(ratelimit.New(rate, burst))
+}
+
+// WithLimiter is like WithRateLimit(), but uses the given rate limiter, which can be shared with other pipes or pools.
+//
+// A limiter created with a rate of 0 or less never refills, so only its first burst items are processed.
+func WithLimiter(l *ratelimit.Limiter) Option {
+	return func(c *config) {
+		c.limiter = l
+	}
+}
+
+// WithOrderedOutput makes the Pipe emit results in the order of the input items, rather than in the order they finish.
+//
+// Items are still processed concurrently, but a result that finishes early is held back until all results before it have been emitted.
+// At most window items are processed or held back at a time; once the window is full, the Pipe stops taking input items
+// until the oldest one is emitted. If window is 0 or negative, the number of workers is used.
+func WithOrderedOutput(window int) Option {
+	return func(c *config) {
+		c.ordered = true
+		c.window = window
+	}
+}
+
+// config holds the settings of a Pipe, as set by its options.
+type config struct {
+	workers int
+	pool    Pool
+	limiter *ratelimit.Limiter
+
+	ordered bool
+	window  int
+
+	policy  ErrorPolicy
+	onPanic func(*pool.PanicError)
+}
+
+func newConfig(workers int, opts []Option) *config {
+	c := &config{workers: workers}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.window <= 0 {
+		c.window = max(workers, 1)
 	}
+	return c
+}
+
+// newPool returns the Pool set by WithPool(), or a new pool.Pool.
+func (c *config) newPool() Pool {
+	if c.pool != nil {
+		return c.pool
+	}
+	return pool.New(c.workers)
+}
+
+// limit blocks until the rate limiter, if any, allows the next item to be processed, or until ctx is done.
+//
+// The limiter gives up right away on a token that would only arrive after the deadline of ctx, so it waits on a context
+// without the deadline instead, which is cancelled once ctx is done.
+func (c *config) limit(ctx context.Context) {
+	if c.limiter == nil {
+		return
+	}
+
+	wctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	defer context.AfterFunc(ctx, cancel)()
+
+	_ = c.limiter.Wait(wctx)
 }
 
 // PipeFromChan creates a pipe that processes items from the input channel using the provided function and a specified number of workers.
 //
 // The pipe can be customized by providing a custom Pool implementation or a Pool implementation from a different package using WithPool().
+// By default, results are emitted in the order they finish; use WithOrderedOutput() to emit them in input order.
+//
+// A panic in fn does not stop the other items from being processed; it is re-raised as a *pool.PanicError
+// from the pipe's goroutine once all items have been processed, unless it is handled using WithPanicHandler().
 func PipeFromChan[I, O any](fn func(I) O, in <-chan I, workers int, opts ...Option) <-chan O {
-	var p Pool = pool.New(workers)
-	for _, opt := range opts {
-		opt(&p)
-	}
+	return run(fn, in, newConfig(workers, opts))
+}
 
-	out := make(chan O)
+// PipeFromSlice creates a pipe that processes items from the input slice using the provided function and a specified number of workers.
+//
+// The pipe can be customized by providing a custom Pool implementation or a Pool implementation from a different package using WithPool().
+// By default, results are emitted in the order they finish; use WithOrderedOutput() to emit them in the order of the slice.
+func PipeFromSlice[I, O any](fn func(I) O, items []I, workers int, opts ...Option) <-chan O {
+	c := newConfig(workers, opts)
+
+	in := make(chan I)
 
 	go func() {
-		defer close(out)
-		defer p.Wait()
-		for item := range in {
-			p.Go(func() {
-				out <- fn(item)
-			})
+		for _, item := range items {
+			in <- item
 		}
+		close(in)
 	}()
 
-	return out
+	return run(fn, in, c)
 }
 
-// PipeFromSlice creates a pipe that processes items from the input slice using the provided function and a specified number of workers.
+// PipeFromChanErr creates a pipe like PipeFromChan(), using a function that can return an error.
 //
-// The pipe can be customized by providing a custom Pool implementation or a Pool implementation from a different package using WithPool().
-func PipeFromSlice[I, O any](fn func(I) O, items []I, workers int, opts ...Option) <-chan O {
-	var p Pool = pool.New(workers)
-	for _, opt := range opts {
-		opt(&p)
-	}
+// The items that failed are not emitted; what happens with their errors is selected by WithErrorPolicy().
+// The returned Errors can be used to retrieve the errors once the output channel is closed.
+// A panic in fn is handled as an error of type *pool.PanicError.
+func PipeFromChanErr[I, O any](fn func(I) (O, error), in <-chan I, workers int, opts ...Option) (<-chan O, *Errors) {
+	return runErr(fn, in, newConfig(workers, opts))
+}
+
+// PipeFromSliceErr creates a pipe like PipeFromSlice(), using a function that can return an error.
+//
+// The items that failed are not emitted; what happens with their errors is selected by WithErrorPolicy().
+// The returned Errors can be used to retrieve the errors once the output channel is closed.
+// A panic in fn is handled as an error of type *pool.PanicError.
+func PipeFromSliceErr[I, O any](fn func(I) (O, error), items []I, workers int, opts ...Option) (<-chan O, *Errors) {
+	c := newConfig(workers, opts)
 
 	in := make(chan I)
-	out := make(chan O)
 
 	go func() {
 		for _, item := range items {
@@ -62,11 +160,25 @@ func PipeFromSlice[I, O any](fn func(I) O, items []I, workers int, opts ...Optio
 		close(in)
 	}()
 
+	return runErr(fn, in, c)
+}
+
+// run processes the items from the input channel on the pool of the config, emitting the results in the order they finish,
+// or in input order if the config is ordered.
+func run[I, O any](fn func(I) O, in <-chan I, c *config) <-chan O {
+	if c.ordered {
+		return runOrdered(fn, in, c)
+	}
+
+	p := c.newPool()
+	out := make(chan O)
+
 	go func() {
 		defer close(out)
-		defer p.Wait()
+		defer wait(p, c)
 		for item := range in {
 			p.Go(func() {
+				c.limit(context.Background())
 				out <- fn(item)
 			})
 		}
@@ -75,11 +187,49 @@ func PipeFromSlice[I, O any](fn func(I) O, items []I, workers int, opts ...Optio
 	return out
 }
 
+// runOrdered processes the items like run, but gives each item a slot in a queue of at most c.window slots.
+// Results are emitted by going through the slots in input order, and a full queue blocks the input until the oldest slot is emitted.
+//
+// A slot is filled even if fn panics, so that the results after it are still emitted.
+func runOrdered[I, O any](fn func(I) O, in <-chan I, c *config) <-chan O {
+	p := c.newPool()
+	// the slot being emitted has already left the channel, so it only buffers the other window-1 slots
+	slots := make(chan chan result[O], c.window-1)
+	out := make(chan O)
+
+	go func() {
+		defer close(slots)
+		defer wait(p, c)
+		for item := range in {
+			slot := make(chan result[O], 1)
+			slots <- slot
+			p.Go(func() {
+				var r result[O]
+				defer func() { slot <- r }()
+				c.limit(context.Background())
+				r = result[O]{value: fn(item), ok: true}
+			})
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		for slot := range slots {
+			if r := <-slot; r.ok {
+				out <- r.value
+			}
+		}
+	}()
+
+	return out
+}
+
 // Collect gathers all items from the output channel into a slice and blocks until the channel is closed.
 func Collect[O any](out <-chan O) []O {
 	var results []O
 	for result := range out {
 		results = append(results, result)
 	}
+
 	return results
 }
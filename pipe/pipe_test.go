@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kiriyms/conpats/pipe"
 	"github.com/sourcegraph/conc/pool"
@@ -192,4 +194,88 @@ func TestPipe(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("respects rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now()
+		p := pipe.PipeFromSlice(func(x int) int {
+			return x
+		}, []int{1, 2, 3, 4, 5, 6}, 6, pipe.WithRateLimit(200, 1))
+		results := pipe.Collect(p)
+
+		if len(results) != 6 {
+			t.Fatalf("expected 6 results, got %d", len(results))
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("expected rate limited pipe to take at least 20ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("emits results in input order", func(t *testing.T) {
+		t.Parallel()
+
+		items := make([]int, 100)
+		for i := range items {
+			items[i] = i
+		}
+
+		p := pipe.PipeFromSlice(func(x int) int {
+			time.Sleep(time.Duration(x%7) * 100 * time.Microsecond)
+			return x * 2
+		}, items, 8, pipe.WithOrderedOutput(16))
+		results := pipe.Collect(p)
+
+		if len(results) != len(items) {
+			t.Fatalf("expected %d results, got %d", len(items), len(results))
+		}
+		for i, v := range results {
+			if v != i*2 {
+				t.Errorf("expected %d at index %d, got %d", i*2, i, v)
+			}
+		}
+	})
+
+	t.Run("bounds the reorder window", func(t *testing.T) {
+		t.Parallel()
+
+		in := make(chan int)
+		var started atomic.Int64
+		release := make(chan struct{})
+
+		out := pipe.PipeFromChan(func(x int) int {
+			started.Add(1)
+			if x == 0 {
+				<-release
+			}
+			return x
+		}, in, 8, pipe.WithOrderedOutput(3))
+
+		sent := make(chan struct{})
+		go func() {
+			defer close(in)
+			for i := 0; i < 10; i++ {
+				in <- i
+			}
+			close(sent)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		if n := started.Load(); n > 3 {
+			t.Errorf("expected at most 3 items in flight while the first one is blocked, got %d", n)
+		}
+		select {
+		case <-sent:
+			t.Error("expected input to be blocked by the full window")
+		default:
+		}
+
+		close(release)
+		results := pipe.Collect(out)
+		for i, v := range results {
+			if v != i {
+				t.Errorf("expected %d at index %d, got %d", i, i, v)
+			}
+		}
+	})
 }
@@ -0,0 +1,279 @@
+package pipe
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+// Pipeline runs a chain of stages that share one context and one lifecycle.
+//
+// A new pipeline must be created using NewPipeline(). Its first stage is created using From() or FromChan(),
+// and further stages are appended using Map(), Filter(), FlatMap(), Batch() and Tap(). Every stage starts as soon as it is appended.
+// The output of the last stage must be read using Out(), after which Wait() returns the first error of any stage.
+//
+// Each stage can emit items of a different type than the stage it is appended to.
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg sync.WaitGroup
+
+	mu  sync.Mutex
+	err error
+}
+
+// Stage is a handle to a stage of a Pipeline, emitting items of type T.
+type Stage[T any] struct {
+	pipeline *Pipeline
+	out      <-chan T
+}
+
+// NewPipeline creates a new Pipeline, whose stages receive a child context of ctx.
+//
+// Cancelling ctx stops all stages. The child context is also cancelled when any stage fails.
+func NewPipeline(ctx context.Context) *Pipeline {
+	cctx, cancel := context.WithCancel(ctx)
+
+	return &Pipeline{
+		parent: ctx,
+		ctx:    cctx,
+		cancel: cancel,
+	}
+}
+
+// Wait blocks until all stages are finished and returns the first error returned by any stage, or the error of the context
+// given to NewPipeline() if it was cancelled. A panic in a stage is returned as a *pool.PanicError, and cancels the other stages like an error.
+//
+// The output of the last stage must be read until it is closed, otherwise Wait blocks until the context is cancelled.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	return p.parent.Err()
+}
+
+// fail records the error of a stage, if it is the first one, and cancels the pipeline.
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	if p.err == nil && p.ctx.Err() == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+
+	p.cancel()
+}
+
+// recover fails the pipeline with a *pool.PanicError if the stage function it is deferred in panicked,
+// so that the panic cancels the other stages right away instead of once the stage is finished.
+func (p *Pipeline) recover() {
+	if r := recover(); r != nil {
+		p.fail(&pool.PanicError{Value: r, Stack: debug.Stack()})
+	}
+}
+
+// Out returns the output channel of the stage, which is closed once the stage is finished.
+//
+// Only the output of the last stage should be read; the outputs of the other stages are read by the stages appended to them.
+func (s *Stage[T]) Out() <-chan T {
+	return s.out
+}
+
+// From creates the first stage of the pipeline, emitting the items of the slice.
+func From[T any](p *Pipeline, items []T) *Stage[T] {
+	out := make(chan T)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		for _, item := range items {
+			select {
+			case out <- item:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &Stage[T]{pipeline: p, out: out}
+}
+
+// FromChan creates the first stage of the pipeline, emitting the items of the channel until it is closed.
+//
+// Once the pipeline is cancelled, the remaining items of the channel are abandoned.
+func FromChan[T any](p *Pipeline, in <-chan T) *Stage[T] {
+	out := make(chan T)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- item:
+				case <-p.ctx.Done():
+					return
+				}
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &Stage[T]{pipeline: p, out: out}
+}
+
+// Map appends a stage that transforms each item using fn, running on the given number of workers.
+//
+// An error returned by fn fails the pipeline, cancelling all stages.
+func Map[I, O any](s *Stage[I], workers int, fn func(context.Context, I) (O, error)) *Stage[O] {
+	return appendStage(s, workers, func(ctx context.Context, item I, emit func(O) bool) error {
+		v, err := fn(ctx, item)
+		if err != nil {
+			return err
+		}
+		emit(v)
+		return nil
+	})
+}
+
+// Filter appends a stage that only emits the items for which fn returns true, running on the given number of workers.
+//
+// An error returned by fn fails the pipeline, cancelling all stages.
+func Filter[T any](s *Stage[T], workers int, fn func(context.Context, T) (bool, error)) *Stage[T] {
+	return appendStage(s, workers, func(ctx context.Context, item T, emit func(T) bool) error {
+		keep, err := fn(ctx, item)
+		if err != nil {
+			return err
+		}
+		if keep {
+			emit(item)
+		}
+		return nil
+	})
+}
+
+// FlatMap appends a stage that transforms each item into any number of items using fn, running on the given number of workers.
+//
+// The items returned for one input item are emitted together, in order. An error returned by fn fails the pipeline, cancelling all stages.
+func FlatMap[I, O any](s *Stage[I], workers int, fn func(context.Context, I) ([]O, error)) *Stage[O] {
+	return appendStage(s, workers, func(ctx context.Context, item I, emit func(O) bool) error {
+		vs, err := fn(ctx, item)
+		if err != nil {
+			return err
+		}
+		for _, v := range vs {
+			if !emit(v) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// Tap appends a stage that calls fn for each item and emits the item unchanged, running on the given number of workers.
+//
+// An error returned by fn fails the pipeline, cancelling all stages.
+func Tap[T any](s *Stage[T], workers int, fn func(context.Context, T) error) *Stage[T] {
+	return appendStage(s, workers, func(ctx context.Context, item T, emit func(T) bool) error {
+		if err := fn(ctx, item); err != nil {
+			return err
+		}
+		emit(item)
+		return nil
+	})
+}
+
+// Batch appends a stage that groups items into slices of the given size. The last batch holds the remaining items, if any.
+//
+// A size of 0 or less is treated as 1.
+func Batch[T any](s *Stage[T], size int) *Stage[[]T] {
+	size = max(size, 1)
+	p := s.pipeline
+	out := make(chan []T)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+
+		batch := make([]T, 0, size)
+		emit := func() bool {
+			select {
+			case out <- batch:
+				batch = make([]T, 0, size)
+				return true
+			case <-p.ctx.Done():
+				return false
+			}
+		}
+
+		for item := range s.out {
+			batch = append(batch, item)
+			if len(batch) == size && !emit() {
+				return
+			}
+		}
+		if len(batch) > 0 && p.ctx.Err() == nil {
+			emit()
+		}
+	}()
+
+	return &Stage[[]T]{pipeline: p, out: out}
+}
+
+// appendStage appends a stage that runs fn for each item of s on a pool.ContextPool with the given number of workers.
+// fn emits its results using emit, which returns false once the pipeline is cancelled.
+func appendStage[I, O any](s *Stage[I], workers int, fn func(ctx context.Context, item I, emit func(O) bool) error) *Stage[O] {
+	p := s.pipeline
+	cp := pool.New(workers).WithErrors().WithContext(p.ctx)
+	out := make(chan O)
+
+	emit := func(v O) bool {
+		select {
+		case out <- v:
+			return true
+		case <-p.ctx.Done():
+			return false
+		}
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(out)
+
+		for item := range s.out {
+			err := cp.GoContext(p.ctx, func(ctx context.Context) error {
+				defer p.recover()
+				if err := fn(ctx, item, emit); err != nil {
+					p.fail(err)
+				}
+				return nil
+			})
+			if err != nil {
+				break
+			}
+		}
+
+		// jobs recover their own panics, so they never return errors
+		cp.Wait()
+	}()
+
+	return &Stage[O]{pipeline: p, out: out}
+}
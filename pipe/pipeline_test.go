@@ -0,0 +1,204 @@
+package pipe_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pipe"
+	cpool "github.com/kiriyms/conpats/pool"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chains typed stages", func(t *testing.T) {
+		t.Parallel()
+
+		p := pipe.NewPipeline(context.Background())
+		var tapped atomic.Int64
+
+		nums := pipe.From(p, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+		even := pipe.Filter(nums, 2, func(ctx context.Context, n int) (bool, error) {
+			return n%2 == 0, nil
+		})
+		twice := pipe.FlatMap(even, 2, func(ctx context.Context, n int) ([]int, error) {
+			return []int{n, n}, nil
+		})
+		tapped2 := pipe.Tap(twice, 1, func(ctx context.Context, n int) error {
+			tapped.Add(1)
+			return nil
+		})
+		strs := pipe.Map(tapped2, 3, func(ctx context.Context, n int) (string, error) {
+			return strconv.Itoa(n), nil
+		})
+
+		results := pipe.Collect(strs.Out())
+		if err := p.Wait(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		sort.Strings(results)
+		expected := []string{"10", "10", "2", "2", "4", "4", "6", "6", "8", "8"}
+		if len(results) != len(expected) {
+			t.Fatalf("expected %d results, got %d", len(expected), len(results))
+		}
+		for i, v := range expected {
+			if results[i] != v {
+				t.Errorf("expected %s, got %s", v, results[i])
+			}
+		}
+		if tapped.Load() != 10 {
+			t.Errorf("expected 10 tapped items, got %d", tapped.Load())
+		}
+	})
+
+	t.Run("batches items", func(t *testing.T) {
+		t.Parallel()
+
+		p := pipe.NewPipeline(context.Background())
+		batches := pipe.Batch(pipe.From(p, []int{1, 2, 3, 4, 5, 6, 7}), 3)
+
+		results := pipe.Collect(batches.Out())
+		if err := p.Wait(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		sizes := []int{3, 3, 1}
+		if len(results) != len(sizes) {
+			t.Fatalf("expected %d batches, got %d", len(sizes), len(results))
+		}
+		for i, size := range sizes {
+			if len(results[i]) != size {
+				t.Errorf("expected batch %d of size %d, got %d", i, size, len(results[i]))
+			}
+		}
+	})
+
+	t.Run("error in one stage cancels the others", func(t *testing.T) {
+		t.Parallel()
+
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; i < 1000; i++ {
+				in <- i
+			}
+		}()
+
+		p := pipe.NewPipeline(context.Background())
+		errTest := errors.New("test")
+
+		failing := pipe.Map(pipe.FromChan(p, in), 2, func(ctx context.Context, n int) (int, error) {
+			if n == 10 {
+				return 0, errTest
+			}
+			return n, nil
+		})
+		slow := pipe.Tap(failing, 2, func(ctx context.Context, n int) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Millisecond):
+				return nil
+			}
+		})
+
+		pipe.Collect(slow.Out())
+		if err := p.Wait(); !errors.Is(err, errTest) {
+			t.Errorf("expected error %v, got %v", errTest, err)
+		}
+	})
+
+	t.Run("returns context error when cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := pipe.NewPipeline(ctx)
+
+		in := make(chan int)
+		out := pipe.Map(pipe.FromChan(p, in), 1, func(ctx context.Context, n int) (int, error) {
+			return n, nil
+		})
+
+		in <- 1
+		<-out.Out()
+		cancel()
+
+		pipe.Collect(out.Out())
+		if err := p.Wait(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("returns panics as errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := pipe.NewPipeline(context.Background())
+		out := pipe.Map(pipe.From(p, []int{1, 2, 3}), 1, func(ctx context.Context, n int) (int, error) {
+			if n == 2 {
+				panic("boom")
+			}
+			return n, nil
+		})
+
+		pipe.Collect(out.Out())
+		var pe *cpool.PanicError
+		if err := p.Wait(); !errors.As(err, &pe) {
+			t.Errorf("expected *pool.PanicError, got %v", err)
+		}
+	})
+
+	t.Run("panic in one stage cancels the others", func(t *testing.T) {
+		t.Parallel()
+
+		// the source never closes, so the panic has to cancel the pipeline for it to finish
+		in := make(chan int)
+		go func() {
+			for i := 0; ; i++ {
+				select {
+				case in <- i:
+				case <-time.After(time.Second):
+					return
+				}
+			}
+		}()
+
+		p := pipe.NewPipeline(context.Background())
+		var emitted atomic.Int64
+
+		panicking := pipe.Map(pipe.FromChan(p, in), 1, func(ctx context.Context, n int) (int, error) {
+			if n == 0 {
+				panic("boom")
+			}
+			return n, nil
+		})
+		counted := pipe.Tap(panicking, 1, func(ctx context.Context, n int) error {
+			emitted.Add(1)
+			return nil
+		})
+
+		done := make(chan error)
+		go func() {
+			pipe.Collect(counted.Out())
+			done <- p.Wait()
+		}()
+
+		select {
+		case err := <-done:
+			var pe *cpool.PanicError
+			if !errors.As(err, &pe) {
+				t.Errorf("expected *pool.PanicError, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the panic to cancel the pipeline")
+		}
+		if n := emitted.Load(); n > 1 {
+			t.Errorf("expected the stages to stop after the panic, got %d items", n)
+		}
+	})
+}
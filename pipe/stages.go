@@ -0,0 +1,133 @@
+package pipe
+
+import (
+	"context"
+	"time"
+)
+
+// FilterFromChan creates a pipe that only emits the items from the input channel for which fn returns true,
+// calling fn concurrently on a specified number of workers.
+//
+// Like PipeFromChan(), the pipe can be customized using WithPool(), WithRateLimit(), WithLimiter(), WithOrderedOutput() and WithPanicHandler().
+func FilterFromChan[I any](fn func(I) bool, in <-chan I, workers int, opts ...Option) <-chan I {
+	results := run(func(item I) result[I] {
+		return result[I]{value: item, ok: fn(item)}
+	}, in, newConfig(workers, opts))
+
+	out := make(chan I)
+
+	go func() {
+		defer close(out)
+		for r := range results {
+			if r.ok {
+				out <- r.value
+			}
+		}
+	}()
+
+	return out
+}
+
+// FlatMapFromChan creates a pipe that transforms each item from the input channel into any number of items using fn,
+// calling fn concurrently on a specified number of workers.
+//
+// The items returned for one input item are emitted together, in order.
+// Like PipeFromChan(), the pipe can be customized using WithPool(), WithRateLimit(), WithLimiter(), WithOrderedOutput() and WithPanicHandler().
+func FlatMapFromChan[I, O any](fn func(I) []O, in <-chan I, workers int, opts ...Option) <-chan O {
+	results := run(fn, in, newConfig(workers, opts))
+
+	out := make(chan O)
+
+	go func() {
+		defer close(out)
+		for vs := range results {
+			for _, v := range vs {
+				out <- v
+			}
+		}
+	}()
+
+	return out
+}
+
+// TapFromChan creates a pipe that calls fn for each item from the input channel and emits the item unchanged,
+// calling fn concurrently on a specified number of workers.
+//
+// Like PipeFromChan(), the pipe can be customized using WithPool(), WithRateLimit(), WithLimiter(), WithOrderedOutput() and WithPanicHandler().
+func TapFromChan[I any](fn func(I), in <-chan I, workers int, opts ...Option) <-chan I {
+	return run(func(item I) I {
+		fn(item)
+		return item
+	}, in, newConfig(workers, opts))
+}
+
+// BatchFromChan creates a pipe that groups the items from the input channel into slices of up to size items.
+//
+// A batch is emitted once it holds size items, or once maxLatency has passed since its first item was buffered, whichever comes first.
+// When the input channel is closed, the remaining items are emitted as a final partial batch.
+// A size of 0 or less is treated as 1, and a maxLatency of 0 or less disables the time limit.
+// While a batch is waiting to be read, no more items are read from the input channel.
+func BatchFromChan[I any](in <-chan I, size int, maxLatency time.Duration) <-chan []I {
+	return BatchFromChanCtx(context.Background(), in, size, maxLatency)
+}
+
+// BatchFromChanCtx creates a pipe like BatchFromChan(), which stops once ctx is cancelled.
+//
+// Once ctx is cancelled, the pipe stops reading the input channel and closes the output channel, abandoning the buffered items.
+func BatchFromChanCtx[I any](ctx context.Context, in <-chan I, size int, maxLatency time.Duration) <-chan []I {
+	size = max(size, 1)
+	out := make(chan []I)
+
+	go func() {
+		defer close(out)
+
+		var batch []I
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+
+			select {
+			case out <- batch:
+				batch = nil
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+
+				batch = append(batch, item)
+				if len(batch) == 1 && maxLatency > 0 {
+					timer = time.NewTimer(maxLatency)
+					timeout = timer.C
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,172 @@
+package pipe_test
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pipe"
+	"github.com/sourcegraph/conc/pool"
+)
+
+func generate(n int) <-chan int {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < n; i++ {
+			in <- i
+		}
+	}()
+	return in
+}
+
+func TestStages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		out := pipe.FilterFromChan(func(x int) bool {
+			return x%3 == 0
+		}, generate(30), 4)
+		results := pipe.Collect(out)
+		sort.Ints(results)
+
+		if len(results) != 10 {
+			t.Fatalf("expected 10 results, got %d", len(results))
+		}
+		for i, v := range results {
+			if v != i*3 {
+				t.Errorf("expected %d, got %d", i*3, v)
+			}
+		}
+	})
+
+	t.Run("filter with ordered output", func(t *testing.T) {
+		t.Parallel()
+
+		out := pipe.FilterFromChan(func(x int) bool {
+			return x%2 == 1
+		}, generate(50), 4, pipe.WithOrderedOutput(8))
+		results := pipe.Collect(out)
+
+		for i, v := range results {
+			if v != i*2+1 {
+				t.Errorf("expected %d at index %d, got %d", i*2+1, i, v)
+			}
+		}
+	})
+
+	t.Run("flat map", func(t *testing.T) {
+		t.Parallel()
+
+		out := pipe.FlatMapFromChan(func(x int) []int {
+			vs := make([]int, x)
+			for i := range vs {
+				vs[i] = x
+			}
+			return vs
+		}, generate(5), 2)
+		results := pipe.Collect(out)
+		sort.Ints(results)
+
+		expected := []int{1, 2, 2, 3, 3, 3, 4, 4, 4, 4}
+		if len(results) != len(expected) {
+			t.Fatalf("expected %d results, got %d", len(expected), len(results))
+		}
+		for i, v := range expected {
+			if results[i] != v {
+				t.Errorf("expected %d, got %d", v, results[i])
+			}
+		}
+	})
+
+	t.Run("tap with another Pool implementation", func(t *testing.T) {
+		t.Parallel()
+
+		var sum atomic.Int64
+		out := pipe.TapFromChan(func(x int) {
+			sum.Add(int64(x))
+		}, generate(10), 3, pipe.WithPool(pool.New()))
+		results := pipe.Collect(out)
+
+		if len(results) != 10 {
+			t.Errorf("expected 10 results, got %d", len(results))
+		}
+		if sum.Load() != 45 {
+			t.Errorf("expected tapped sum of 45, got %d", sum.Load())
+		}
+	})
+
+	t.Run("batch by size", func(t *testing.T) {
+		t.Parallel()
+
+		results := pipe.Collect(pipe.BatchFromChan(generate(10), 4, 0))
+
+		sizes := []int{4, 4, 2}
+		if len(results) != len(sizes) {
+			t.Fatalf("expected %d batches, got %d", len(sizes), len(results))
+		}
+		next := 0
+		for i, size := range sizes {
+			if len(results[i]) != size {
+				t.Errorf("expected batch %d of size %d, got %d", i, size, len(results[i]))
+			}
+			for _, v := range results[i] {
+				if v != next {
+					t.Errorf("expected %d, got %d", next, v)
+				}
+				next++
+			}
+		}
+	})
+
+	t.Run("batch by latency", func(t *testing.T) {
+		t.Parallel()
+
+		in := make(chan int)
+		out := pipe.BatchFromChan(in, 100, 5*time.Millisecond)
+
+		in <- 1
+		in <- 2
+		select {
+		case batch := <-out:
+			if len(batch) != 2 {
+				t.Errorf("expected batch of 2 items, got %d", len(batch))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected partial batch to be flushed after max latency")
+		}
+
+		in <- 3
+		close(in)
+		if batch := <-out; len(batch) != 1 || batch[0] != 3 {
+			t.Errorf("expected final batch [3], got %v", batch)
+		}
+		if _, ok := <-out; ok {
+			t.Error("expected output to be closed")
+		}
+	})
+
+	t.Run("batch stops on cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan int)
+		out := pipe.BatchFromChanCtx(ctx, in, 10, 0)
+
+		in <- 1
+		cancel()
+
+		select {
+		case _, ok := <-out:
+			if ok {
+				t.Error("expected buffered items to be abandoned")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected output to be closed after cancellation")
+		}
+	})
+}
@@ -2,8 +2,23 @@ package pool
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 )
 
+// ErrJobTimeout is wrapped by the errors of jobs that failed after running past their own timeout or deadline,
+// as set by WithJobTimeout(), GoWithTimeout() or GoWithDeadline().
+//
+// ErrJobTimeout itself wraps context.DeadlineExceeded, so errors.Is() matches both.
+var ErrJobTimeout error = jobTimeoutError{}
+
+type jobTimeoutError struct{}
+
+func (jobTimeoutError) Error() string { return "pool: job timed out" }
+
+func (jobTimeoutError) Unwrap() error { return context.DeadlineExceeded }
+
 // ContextPool extends ErrorPool to handle jobs that expect a context.Context as a parameter and can return errors.
 //
 // A new context pool must be created using New().WithErrors().WithContext(). Jobs can be submitted using Go() or TryGo().
@@ -12,6 +27,7 @@ type ContextPool struct {
 	errorPool *ErrorPool
 
 	cancelOnErr bool
+	jobTimeout  time.Duration
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -20,36 +36,85 @@ type ContextPool struct {
 // Go submits a job to the context pool.
 //
 // If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError, which also cancels the context when WithCancelOnErr() is set.
 func (p *ContextPool) Go(job func(context.Context) error) {
-	p.errorPool.Go(func() error {
-		err := job(p.ctx)
-		if err != nil && p.cancelOnErr {
-			p.cancel()
-			if p.errorPool.onlyFirstErr {
-				p.errorPool.addErr(err)
-			}
-		}
+	_ = p.submit(context.Background(), p.task(job, p.jobTimeout, time.Time{}), true)
+}
 
-		return err
-	})
+// GoWithTimeout submits a job to the context pool, which receives a child context of the pool context that is cancelled
+// once the job has been running for the given timeout, overriding the default timeout set by WithJobTimeout().
+//
+// If the job returns an error after its timeout expired, the error is wrapped with ErrJobTimeout.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ContextPool) GoWithTimeout(timeout time.Duration, job func(context.Context) error) {
+	_ = p.submit(context.Background(), p.task(job, timeout, time.Time{}), true)
+}
+
+// GoWithDeadline submits a job to the context pool, which receives a child context of the pool context that is cancelled
+// at the given deadline, overriding the default timeout set by WithJobTimeout().
+//
+// If the job returns an error after its deadline passed, the error is wrapped with ErrJobTimeout.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ContextPool) GoWithDeadline(deadline time.Time, job func(context.Context) error) {
+	_ = p.submit(context.Background(), p.task(job, 0, deadline), true)
+}
+
+// GoPriority submits a job with the given priority to the context pool.
+//
+// Queued jobs with a higher priority are picked up by workers first; jobs submitted using Go() have a priority of 0.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ContextPool) GoPriority(priority int, job func(context.Context) error) {
+	t := p.task(job, p.jobTimeout, time.Time{})
+	t.priority = priority
+	_ = p.submit(context.Background(), t, true)
+}
+
+// GoWithID submits a job to the context pool, wrapping its error, if any, in a *JobError with the given identifier.
+// The identifier is also returned by Stop() if the job is dropped.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ContextPool) GoWithID(id string, job func(context.Context) error) {
+	t := p.task(func(ctx context.Context) error {
+		return withID(id, func() error { return job(ctx) })()
+	}, p.jobTimeout, time.Time{})
+	t.id = id
+	_ = p.submit(context.Background(), t, true)
 }
 
-// TryGo attempts to submit a job to the context pool.
+// TryGo attempts to submit a job to the context pool without blocking.
 //
-// If a job is submitted after Wait() has been called, it will be dropped and false is returned.
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
 // Otherwise, true is returned.
 func (p *ContextPool) TryGo(job func(context.Context) error) bool {
-	return p.errorPool.TryGo(func() error {
-		err := job(p.ctx)
-		if err != nil && p.cancelOnErr {
-			p.cancel()
-			if p.errorPool.onlyFirstErr {
-				p.errorPool.addErr(err)
-			}
-		}
+	return p.submit(context.Background(), p.task(job, p.jobTimeout, time.Time{}), false) == nil
+}
 
-		return err
-	})
+// GoWeighted submits a job with the given weight to the context pool.
+//
+// GoWeighted blocks while admitting the job would exceed the capacity set by WithWeightCapacity(). See Pool.GoWeighted() for details.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ContextPool) GoWeighted(weight int, job func(context.Context) error) {
+	t := p.task(job, p.jobTimeout, time.Time{})
+	t.weight = weight
+	_ = p.submit(context.Background(), t, true)
+}
+
+// TryGoWeighted attempts to submit a job with the given weight to the context pool without blocking.
+//
+// If the job cannot be admitted right away, it will be dropped and false is returned. Otherwise, true is returned.
+func (p *ContextPool) TryGoWeighted(weight int, job func(context.Context) error) bool {
+	t := p.task(job, p.jobTimeout, time.Time{})
+	t.weight = weight
+	return p.submit(context.Background(), t, false) == nil
+}
+
+// GoContext submits a job to the context pool, waiting for room in the queue until ctx is done.
+//
+// The given ctx only bounds the wait; the job itself still receives the context of the pool.
+// If a job is submitted after Wait() has been called, ErrClosed is returned.
+// If ctx is done before the job could be queued, the job is dropped and ctx.Err() is returned.
+func (p *ContextPool) GoContext(ctx context.Context, job func(context.Context) error) error {
+	return p.submit(ctx, p.task(job, p.jobTimeout, time.Time{}), true)
 }
 
 // Collect blocks until all submitted jobs are finished and returns collected errors.
@@ -72,3 +137,121 @@ func (p *ContextPool) Wait() []error {
 
 	return err
 }
+
+// CollectErr blocks until all submitted jobs are finished and returns collected errors joined using errors.Join(), or nil if there are none.
+//
+// Like Collect(), this does not close the context pool.
+func (p *ContextPool) CollectErr() error {
+	return errors.Join(p.Collect()...)
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+//
+// Like Wait(), this closes the context pool. With WithOnlyFirstErr(), only the first error is joined.
+func (p *ContextPool) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+// Pause stops the workers from picking up queued jobs, letting the running jobs finish.
+//
+// While the context pool is paused, Collect() only waits for the running jobs to finish. See Pool.Pause() for details.
+func (p *ContextPool) Pause() {
+	p.errorPool.Pause()
+}
+
+// Resume lets the workers of a paused context pool pick up queued jobs again.
+func (p *ContextPool) Resume() {
+	p.errorPool.Resume()
+}
+
+// Paused reports whether the context pool is paused.
+func (p *ContextPool) Paused() bool {
+	return p.errorPool.Paused()
+}
+
+// Stop closes the context pool like Wait(), but drops the queued jobs instead of running them,
+// cancels the context of the running jobs, and blocks until they return or ctx is done.
+//
+// Stop() returns the identifiers of the dropped jobs, as given to GoWithID(), and ctx.Err() if ctx is done before the running jobs return.
+// See Pool.Stop() for details. The errors of the jobs that did run can be retrieved using Collect() or Wait() afterwards.
+func (p *ContextPool) Stop(ctx context.Context) ([]string, error) {
+	tasks, err := p.stop(ctx)
+	return ids(tasks), err
+}
+
+// stop implements Stop(), returning the dropped tasks.
+func (p *ContextPool) stop(ctx context.Context) ([]*task, error) {
+	p.cancel()
+	return p.errorPool.pool.stop(ctx)
+}
+
+// Resize changes the number of workers in the context pool.
+//
+// Growing the context pool spawns new workers immediately. Shrinking the context pool lets busy workers finish their current job before they exit.
+func (p *ContextPool) Resize(workers int) {
+	p.errorPool.Resize(workers)
+}
+
+// Workers returns the current number of workers in the context pool.
+func (p *ContextPool) Workers() int {
+	return p.errorPool.Workers()
+}
+
+// submit submits the task to the underlying pool. The task waits for the rate limiter, if any, until the pool context is done.
+func (p *ContextPool) submit(ctx context.Context, t *task, block bool) error {
+	t.ctx = p.ctx
+	return p.errorPool.pool.submit(ctx, t, block)
+}
+
+// task returns a task of weight 1 running the given job with the pool context. See wrap() for the timeout and the deadline.
+func (p *ContextPool) task(job func(context.Context) error, timeout time.Duration, deadline time.Time) *task {
+	t := &task{weight: 1, ctx: p.ctx}
+	t.fn = p.wrap(t, job, timeout, deadline)
+	return t
+}
+
+// wrap returns a job that passes the pool context to the given job, as an attempt of the task that is retried if configured to,
+// and cancels the pool context on error if configured to.
+//
+// If a timeout or a deadline is given, each attempt of the job receives a child context of the pool context that expires accordingly instead.
+func (p *ContextPool) wrap(t *task, job func(context.Context) error, timeout time.Duration, deadline time.Time) func() {
+	return p.errorPool.collect(func() error {
+		return p.run(t, job, timeout, deadline)
+	})
+}
+
+// run runs the job as an attempt of the task, and cancels the pool context if it fails for good and the pool is configured to.
+func (p *ContextPool) run(t *task, job func(context.Context) error, timeout time.Duration, deadline time.Time) error {
+	err := p.errorPool.retry(t, func() error {
+		return p.attempt(job, timeout, deadline)
+	})
+
+	if err != nil && err != errRetry && p.cancelOnErr {
+		p.cancel()
+		if p.errorPool.onlyFirstErr {
+			p.errorPool.addErr(err)
+		}
+	}
+
+	return err
+}
+
+// attempt runs the job once, wrapping its error with ErrJobTimeout if it ran past its own timeout or deadline.
+func (p *ContextPool) attempt(job func(context.Context) error, timeout time.Duration, deadline time.Time) error {
+	ctx := p.ctx
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadlineCause(p.ctx, deadline, ErrJobTimeout)
+		defer cancel()
+	}
+
+	err := catchErr(func() error { return job(ctx) })
+	if err != nil && context.Cause(ctx) == ErrJobTimeout && !errors.Is(err, ErrJobTimeout) {
+		err = fmt.Errorf("%w: %w", ErrJobTimeout, err)
+	}
+
+	return err
+}
@@ -2,6 +2,7 @@ package pool_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -337,4 +338,121 @@ func TestContextPool(t *testing.T) {
 			t.Errorf("Errors count mismatch; count: %d, collected: %d", errored.Load(), len(errs))
 		}
 	})
+	t.Run("applies default job timeout", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors().WithContext(context.Background(), pool.WithJobTimeout(5*time.Millisecond))
+
+		p.Go(func(c context.Context) error {
+			<-c.Done()
+			return c.Err()
+		})
+		p.Go(func(c context.Context) error {
+			return nil
+		})
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got: %d", len(errs))
+		}
+		if !errors.Is(errs[0], pool.ErrJobTimeout) {
+			t.Errorf("Expected pool.ErrJobTimeout, got: %v", errs[0])
+		}
+		if !errors.Is(errs[0], context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", errs[0])
+		}
+	})
+
+	t.Run("handles GoWithTimeout and GoWithDeadline correctly", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(3).WithErrors().WithContext(context.Background(), pool.WithJobTimeout(time.Hour))
+		var timedOut atomic.Int64
+
+		hang := func(c context.Context) error {
+			select {
+			case <-c.Done():
+				timedOut.Add(1)
+				return c.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		}
+
+		p.GoWithTimeout(5*time.Millisecond, hang)
+		p.GoWithDeadline(time.Now().Add(5*time.Millisecond), hang)
+		p.GoWithTimeout(time.Second, func(c context.Context) error {
+			return fmt.Errorf("intentional error")
+		})
+
+		errs := p.Wait()
+		if len(errs) != 3 {
+			t.Fatalf("Expected 3 errors, got: %d", len(errs))
+		}
+		if timedOut.Load() != 2 {
+			t.Errorf("Expected 2 timed out jobs, got: %d", timedOut.Load())
+		}
+
+		var timeouts int
+		for _, err := range errs {
+			if errors.Is(err, pool.ErrJobTimeout) {
+				timeouts++
+			}
+		}
+		if timeouts != 2 {
+			t.Errorf("Expected 2 pool.ErrJobTimeout errors, got: %d", timeouts)
+		}
+	})
+
+	t.Run("does not report pool cancellation as job timeout", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := pool.New(1).WithErrors().WithContext(ctx, pool.WithJobTimeout(time.Second))
+
+		p.Go(func(c context.Context) error {
+			<-c.Done()
+			return c.Err()
+		})
+		cancel()
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got: %d", len(errs))
+		}
+		if errors.Is(errs[0], pool.ErrJobTimeout) || !errors.Is(errs[0], context.Canceled) {
+			t.Errorf("Expected context.Canceled only, got: %v", errs[0])
+		}
+	})
+	t.Run("joins only first error with WaitErr", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors(pool.WithOnlyFirstErr()).WithContext(context.Background())
+
+		p.GoWithID("first", func(c context.Context) error {
+			return fmt.Errorf("intentional error")
+		})
+		p.Collect()
+		p.GoWithID("second", func(c context.Context) error {
+			return fmt.Errorf("intentional error")
+		})
+		p.GoWithID("third", func(c context.Context) error {
+			panic("intentional panic")
+		})
+
+		err := p.CollectErr()
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 1 {
+			t.Fatalf("Expected only first error to be joined, got %v", err)
+		}
+
+		var je *pool.JobError
+		if !errors.As(err, &je) || (je.ID != "second" && je.ID != "third") {
+			t.Errorf("Expected *pool.JobError of second or third job, got %v", err)
+		}
+
+		if err := p.WaitErr(); err != nil {
+			t.Errorf("Expected nil from WaitErr, got %v", err)
+		}
+	})
 }
@@ -2,7 +2,10 @@ package pool
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 )
 
 type OptionCtx func(*ContextPool)
@@ -15,6 +18,31 @@ func WithCancelOnErr() OptionCtx {
 	}
 }
 
+// WithJobTimeout sets a default timeout for every job of the context pool, measured from the moment the job starts running.
+// Each job receives its own child context of the pool context, which is cancelled once the timeout expires.
+// By default, jobs have no timeout of their own.
+func WithJobTimeout(timeout time.Duration) OptionCtx {
+	return func(p *ContextPool) {
+		p.jobTimeout = timeout
+	}
+}
+
+// JobError wraps the error of a job submitted using GoWithID(), identifying the job that failed.
+type JobError struct {
+	ID  string
+	Err error
+}
+
+// Error returns the identifier of the job followed by its error.
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job %s: %v", e.ID, e.Err)
+}
+
+// Unwrap returns the error of the job.
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
 // ErrorPool extends Pool to handle jobs that return errors.
 //
 // A new error pool must be created using New().WithErrors(). Jobs can be submitted using Go() or TryGo().
@@ -23,6 +51,7 @@ type ErrorPool struct {
 	pool *Pool
 
 	onlyFirstErr bool
+	retryPolicy  *RetryPolicy
 
 	mu   sync.Mutex
 	errs []error
@@ -31,20 +60,64 @@ type ErrorPool struct {
 // Go submits a job to the error pool.
 //
 // If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError.
 func (p *ErrorPool) Go(job func() error) {
-	p.pool.Go(func() {
-		p.addErr(job())
-	})
+	_ = p.pool.submit(context.Background(), p.task(job), true)
+}
+
+// GoPriority submits a job with the given priority to the error pool.
+//
+// Queued jobs with a higher priority are picked up by workers first; jobs submitted using Go() have a priority of 0.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ErrorPool) GoPriority(priority int, job func() error) {
+	t := p.task(job)
+	t.priority = priority
+	_ = p.pool.submit(context.Background(), t, true)
 }
 
-// TryGo attempts to submit a job to the error pool.
+// GoWithID submits a job to the error pool, wrapping its error, if any, in a *JobError with the given identifier.
+// The identifier is also returned by Stop() if the job is dropped.
 //
-// If a job is submitted after Wait() has been called, it will be dropped and false is returned.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ErrorPool) GoWithID(id string, job func() error) {
+	t := p.task(withID(id, job))
+	t.id = id
+	_ = p.pool.submit(context.Background(), t, true)
+}
+
+// TryGo attempts to submit a job to the error pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
 // Otherwise, true is returned.
 func (p *ErrorPool) TryGo(job func() error) bool {
-	return p.pool.TryGo(func() {
-		p.addErr(job())
-	})
+	return p.pool.submit(context.Background(), p.task(job), false) == nil
+}
+
+// GoWeighted submits a job with the given weight to the error pool.
+//
+// GoWeighted blocks while admitting the job would exceed the capacity set by WithWeightCapacity(). See Pool.GoWeighted() for details.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ErrorPool) GoWeighted(weight int, job func() error) {
+	t := p.task(job)
+	t.weight = weight
+	_ = p.pool.submit(context.Background(), t, true)
+}
+
+// TryGoWeighted attempts to submit a job with the given weight to the error pool without blocking.
+//
+// If the job cannot be admitted right away, it will be dropped and false is returned. Otherwise, true is returned.
+func (p *ErrorPool) TryGoWeighted(weight int, job func() error) bool {
+	t := p.task(job)
+	t.weight = weight
+	return p.pool.submit(context.Background(), t, false) == nil
+}
+
+// GoContext submits a job to the error pool, waiting for room in the queue until ctx is done.
+//
+// If a job is submitted after Wait() has been called, ErrClosed is returned.
+// If ctx is done before the job could be queued, the job is dropped and ctx.Err() is returned.
+func (p *ErrorPool) GoContext(ctx context.Context, job func() error) error {
+	return p.pool.submit(ctx, p.task(job), true)
 }
 
 // Collect blocks until all submitted jobs are finished and returns collected errors.
@@ -64,6 +137,58 @@ func (p *ErrorPool) Wait() []error {
 	return p.getErrs()
 }
 
+// CollectErr blocks until all submitted jobs are finished and returns collected errors joined using errors.Join(), or nil if there are none.
+//
+// Like Collect(), this does not close the error pool.
+func (p *ErrorPool) CollectErr() error {
+	return errors.Join(p.Collect()...)
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+//
+// Like Wait(), this closes the error pool. With WithOnlyFirstErr(), only the first error is joined.
+func (p *ErrorPool) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+// Pause stops the workers from picking up queued jobs, letting the running jobs finish.
+//
+// While the error pool is paused, Collect() only waits for the running jobs to finish. See Pool.Pause() for details.
+func (p *ErrorPool) Pause() {
+	p.pool.Pause()
+}
+
+// Resume lets the workers of a paused error pool pick up queued jobs again.
+func (p *ErrorPool) Resume() {
+	p.pool.Resume()
+}
+
+// Paused reports whether the error pool is paused.
+func (p *ErrorPool) Paused() bool {
+	return p.pool.Paused()
+}
+
+// Stop closes the error pool like Wait(), but drops the queued jobs instead of running them,
+// and blocks until the running jobs finish or ctx is done.
+//
+// Stop() returns the identifiers of the dropped jobs, as given to GoWithID(), and ctx.Err() if ctx is done before the running jobs finish.
+// See Pool.Stop() for details. The errors of the jobs that did run can be retrieved using Collect() or Wait() afterwards.
+func (p *ErrorPool) Stop(ctx context.Context) ([]string, error) {
+	return p.pool.Stop(ctx)
+}
+
+// Resize changes the number of workers in the error pool.
+//
+// Growing the error pool spawns new workers immediately. Shrinking the error pool lets busy workers finish their current job before they exit.
+func (p *ErrorPool) Resize(workers int) {
+	p.pool.Resize(workers)
+}
+
+// Workers returns the current number of workers in the error pool.
+func (p *ErrorPool) Workers() int {
+	return p.pool.Workers()
+}
+
 // WithErrors converts the ErrorPool to a ContextPool
 //
 // ContextPool accepts jobs that expect a ctx.context as a parameter and can return errors.
@@ -83,6 +208,53 @@ func (p *ErrorPool) WithContext(ctx context.Context, opts ...OptionCtx) *Context
 	return cp
 }
 
+// task returns a task of weight 1 running the given job, which is retried if configured to and whose error is collected.
+func (p *ErrorPool) task(job func() error) *task {
+	t := &task{weight: 1}
+	t.fn = p.wrap(t, job)
+	return t
+}
+
+// wrap returns a job that runs the given job as an attempt of the task and collects its error.
+func (p *ErrorPool) wrap(t *task, job func() error) func() {
+	return p.collect(func() error {
+		return p.retry(t, job)
+	})
+}
+
+// collect returns a job that collects the error returned by the given job, turning panics into *PanicError.
+// The error of an attempt that is to be retried is not collected.
+func (p *ErrorPool) collect(job func() error) func() {
+	return func() {
+		err := catchErr(job)
+		if err == errRetry {
+			return
+		}
+		p.pool.stats.fail(err)
+		p.pool.hooks.failed(err)
+		p.addErr(err)
+	}
+}
+
+// retry runs the job as an attempt of the task, and marks the task to be retried according to the retry policy of the error pool, if any.
+// The wait before the next attempt is cut short once the context of the task is done.
+func (p *ErrorPool) retry(t *task, job func() error) error {
+	if p.retryPolicy == nil {
+		return catchErr(job)
+	}
+	return p.retryPolicy.attempt(t, job)
+}
+
+// withID returns a job that wraps the error of the given job, including a recovered panic, in a *JobError.
+func withID(id string, job func() error) func() error {
+	return func() error {
+		if err := catchErr(job); err != nil {
+			return &JobError{ID: id, Err: err}
+		}
+		return nil
+	}
+}
+
 func (p *ErrorPool) getErrs() []error {
 	p.mu.Lock()
 	errs := p.errs
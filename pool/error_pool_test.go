@@ -1,6 +1,8 @@
 package pool_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -208,4 +210,96 @@ func TestErrorPool(t *testing.T) {
 			t.Errorf("Errors count mismatch; count: %d, collected: %d", errored.Load(), len(errs))
 		}
 	})
+	t.Run("handles GoContext correctly", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1).WithErrors()
+		release := make(chan struct{})
+		p.Go(func() error {
+			<-release
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := p.GoContext(ctx, func() error { return fmt.Errorf("dropped") })
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+
+		close(release)
+		errs := p.Wait()
+		if errs != nil {
+			t.Errorf("Expected no errors from dropped job, got: %v", errs)
+		}
+	})
+	t.Run("joins errors with WaitErr", func(t *testing.T) {
+		t.Parallel()
+
+		sentinel := errors.New("sentinel")
+		p := pool.New(3).WithErrors()
+
+		for i := 0; i < 10; i++ {
+			p.GoWithID(fmt.Sprintf("job-%d", i), func() error {
+				if i == 4 {
+					return sentinel
+				}
+				if i%3 == 0 {
+					return fmt.Errorf("err%d", i)
+				}
+				return nil
+			})
+		}
+
+		err := p.WaitErr()
+		if err == nil {
+			t.Fatalf("Expected joined error, got nil")
+		}
+		if !errors.Is(err, sentinel) {
+			t.Errorf("Expected joined error to match sentinel, got %v", err)
+		}
+
+		var je *pool.JobError
+		if !errors.As(err, &je) {
+			t.Fatalf("Expected joined error to contain a *pool.JobError, got %v", err)
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("Expected errors.Join-compatible error, got %T", err)
+		}
+		if len(joined.Unwrap()) != 5 {
+			t.Errorf("Expected 5 joined errors, got %d", len(joined.Unwrap()))
+		}
+
+		ids := map[string]bool{}
+		for _, e := range joined.Unwrap() {
+			if errors.As(e, &je) {
+				ids[je.ID] = true
+			}
+		}
+		for _, id := range []string{"job-0", "job-3", "job-4", "job-6", "job-9"} {
+			if !ids[id] {
+				t.Errorf("Expected error of %s to be identified", id)
+			}
+		}
+	})
+
+	t.Run("returns nil from CollectErr and WaitErr if no errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors()
+		p.Go(func() error { return nil })
+
+		if err := p.CollectErr(); err != nil {
+			t.Errorf("Expected nil from CollectErr, got %v", err)
+		}
+
+		p.Go(func() error { return nil })
+
+		if err := p.WaitErr(); err != nil {
+			t.Errorf("Expected nil from WaitErr, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,168 @@
+package pool
+
+import (
+	"context"
+	"time"
+)
+
+// Future is a handle to a single job submitted using Submit(), SubmitErr() or SubmitCtx(),
+// which can be used to wait for the job, get its result, or cancel it.
+type Future[T any] struct {
+	done chan struct{}
+
+	value T
+	err   error
+
+	pool *Pool
+	task *task
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Submit submits a job returning a value to the pool, and returns a Future for it.
+//
+// Submit blocks while all workers are busy and the queue is full.
+// If the job is submitted after Wait() has been called, it is dropped and the Future fails with ErrClosed.
+// If the job panics, the Future fails with the *PanicError, which is also re-raised by Wait() or Collect() of the pool.
+func Submit[T any](p *Pool, job func() T) *Future[T] {
+	f := newFuture[T](p)
+	f.submit(&task{fn: func() {
+		pe := catch(func() { f.value = job() })
+		if pe != nil {
+			f.err = pe
+			p.recovered(pe)
+		}
+	}})
+	return f
+}
+
+// SubmitErr submits a job returning a value and an error to the error pool, and returns a Future for it.
+//
+// The error of the job is both returned by the Future and collected by the error pool. A value returned together with an error is discarded.
+// If the job is submitted after Wait() has been called, it is dropped and the Future fails with ErrClosed.
+func SubmitErr[T any](p *ErrorPool, job func() (T, error)) *Future[T] {
+	f := newFuture[T](p.pool)
+	t := &task{}
+	t.fn = p.collect(func() error {
+		var v T
+		err := p.retry(t, func() (err error) {
+			v, err = job()
+			return err
+		})
+		if err != errRetry {
+			f.set(v, err)
+		}
+		return err
+	})
+	f.submit(t)
+	return f
+}
+
+// SubmitCtx submits a job returning a value and an error to the context pool, and returns a Future for it.
+//
+// The job receives a child context of the pool context, which is also cancelled by Cancel() of the Future.
+// A job cancelled that way that returns an error does not count as failed for the context pool: the error is only returned
+// by the Future, and does not cancel the pool context when WithCancelOnErr() is set.
+// If the job is submitted after Wait() has been called, it is dropped and the Future fails with ErrClosed.
+func SubmitCtx[T any](p *ContextPool, job func(context.Context) (T, error)) *Future[T] {
+	f := newFuture[T](p.errorPool.pool)
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+
+	var v T
+	attempt := func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(f.ctx, cancel)()
+
+		var err error
+		v, err = job(ctx)
+		if err != nil && f.ctx.Err() != nil {
+			f.err = err
+			return nil
+		}
+		return err
+	}
+
+	t := &task{ctx: p.ctx}
+	t.fn = p.errorPool.collect(func() error {
+		err := p.run(t, attempt, p.jobTimeout, time.Time{})
+		if f.err == nil && err != errRetry {
+			f.set(v, err)
+		}
+		return err
+	})
+	f.submit(t)
+	return f
+}
+
+func newFuture[T any](p *Pool) *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
+		pool: p,
+	}
+}
+
+// submit submits the task to the pool, completing the Future once its job is finished, and failing it if the task is dropped.
+// If the context of the task is not nil, it bounds the wait for the rate limiter of the pool.
+func (f *Future[T]) submit(t *task) {
+	t.weight = 1
+	t.done = func() { close(f.done) }
+	t.drop = f.fail
+	f.task = t
+
+	if err := f.pool.submit(context.Background(), t, true); err != nil {
+		f.fail(err)
+	}
+}
+
+func (f *Future[T]) set(v T, err error) {
+	if err != nil {
+		f.err = err
+		return
+	}
+	f.value = v
+}
+
+func (f *Future[T]) fail(err error) {
+	f.err = err
+	if f.cancel != nil {
+		f.cancel()
+	}
+	close(f.done)
+}
+
+// Wait blocks until the job is finished, or has been dropped or cancelled before it started.
+func (f *Future[T]) Wait() {
+	<-f.done
+}
+
+// Done returns a channel that is closed once the job is finished, or has been dropped or cancelled before it started.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result blocks until the job is finished and returns its value and error.
+//
+// If the job was cancelled before it started, the error is context.Canceled.
+// If it was dropped because the pool was closed or stopped, the error is ErrClosed.
+func (f *Future[T]) Result() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Cancel cancels the job.
+//
+// If the job is still queued or waiting to be retried, it is removed from the pool and Cancel() returns true.
+// Otherwise, Cancel() returns false; the context of a running job submitted using SubmitCtx() is cancelled,
+// while other running jobs are left to finish.
+func (f *Future[T]) Cancel() bool {
+	if f.pool.cancel(f.task) {
+		return true
+	}
+
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestFuture(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the result of the job", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2)
+		futures := make([]*pool.Future[int], 10)
+		for i := range futures {
+			futures[i] = pool.Submit(p, func() int { return i * i })
+		}
+
+		for i, f := range futures {
+			v, err := f.Result()
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if v != i*i {
+				t.Errorf("Expected %d, got %d", i*i, v)
+			}
+		}
+		p.Wait()
+	})
+
+	t.Run("Done is closed once the job finishes", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		release := make(chan struct{})
+		f := pool.Submit(p, func() string {
+			<-release
+			return "done"
+		})
+
+		select {
+		case <-f.Done():
+			t.Fatal("Expected Done not to be closed while the job runs")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		close(release)
+		f.Wait()
+		if v, _ := f.Result(); v != "done" {
+			t.Errorf("Expected done, got %s", v)
+		}
+		p.Wait()
+	})
+
+	t.Run("Cancel removes a queued job", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(1))
+		release := make(chan struct{})
+		started := make(chan struct{})
+		blocker := pool.Submit(p, func() int {
+			close(started)
+			<-release
+			return 1
+		})
+		<-started
+
+		ran := false
+		f := pool.Submit(p, func() int {
+			ran = true
+			return 2
+		})
+		if !f.Cancel() {
+			t.Error("Expected Cancel to remove the queued job")
+		}
+		if _, err := f.Result(); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if blocker.Cancel() {
+			t.Error("Expected Cancel not to remove a running job")
+		}
+
+		close(release)
+		p.Wait()
+		if ran {
+			t.Error("Expected the cancelled job not to run")
+		}
+		if s := p.Stats(); s.Dropped != 1 || s.Completed != 1 {
+			t.Errorf("Expected 1 dropped and 1 completed job, got %d and %d", s.Dropped, s.Completed)
+		}
+	})
+
+	t.Run("fails with ErrClosed when dropped", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(1))
+		release := make(chan struct{})
+		started := make(chan struct{})
+		pool.Submit(p, func() int {
+			close(started)
+			<-release
+			return 1
+		})
+		<-started
+		queued := pool.Submit(p, func() int { return 2 })
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			close(release)
+		}()
+		if dropped, _ := p.Stop(context.Background()); len(dropped) != 1 {
+			t.Errorf("Expected 1 dropped job, got %d", len(dropped))
+		}
+		if _, err := queued.Result(); !errors.Is(err, pool.ErrClosed) {
+			t.Errorf("Expected pool.ErrClosed, got %v", err)
+		}
+
+		f := pool.Submit(p, func() int { return 3 })
+		if _, err := f.Result(); !errors.Is(err, pool.ErrClosed) {
+			t.Errorf("Expected pool.ErrClosed after Stop, got %v", err)
+		}
+	})
+
+	t.Run("SubmitErr returns and collects errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors()
+		errTest := errors.New("test")
+
+		ok := pool.SubmitErr(p, func() (int, error) { return 1, nil })
+		failed := pool.SubmitErr(p, func() (int, error) { return 2, errTest })
+
+		if v, err := ok.Result(); v != 1 || err != nil {
+			t.Errorf("Expected 1 and no error, got %d and %v", v, err)
+		}
+		if v, err := failed.Result(); v != 0 || !errors.Is(err, errTest) {
+			t.Errorf("Expected 0 and %v, got %d and %v", errTest, v, err)
+		}
+		if errs := p.Wait(); len(errs) != 1 {
+			t.Errorf("Expected 1 collected error, got %d", len(errs))
+		}
+	})
+
+	t.Run("SubmitCtx Cancel cancels the job context", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors().WithContext(context.Background(), pool.WithCancelOnErr())
+		started := make(chan struct{})
+
+		f := pool.SubmitCtx(p, func(ctx context.Context) (int, error) {
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		<-started
+
+		if f.Cancel() {
+			t.Error("Expected Cancel to return false for a running job")
+		}
+		if _, err := f.Result(); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		other := pool.SubmitCtx(p, func(ctx context.Context) (int, error) {
+			return 1, ctx.Err()
+		})
+		if v, err := other.Result(); v != 1 || err != nil {
+			t.Errorf("Expected the pool context not to be cancelled, got %d and %v", v, err)
+		}
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no collected errors, got %v", errs)
+		}
+	})
+}
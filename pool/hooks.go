@@ -0,0 +1,100 @@
+package pool
+
+import (
+	"errors"
+	"time"
+)
+
+// Hooks holds functions that a pool calls during the lifecycle of its jobs and workers. Any of them can be nil.
+//
+// Hooks are called synchronously on the submitting goroutine (OnSubmit) or on the worker goroutine (all others),
+// so they must be safe for concurrent use and should return quickly.
+type Hooks struct {
+	// OnSubmit is called when a job has been accepted by the pool.
+	OnSubmit func()
+	// OnStart is called when a worker starts running a job.
+	OnStart func()
+	// OnFinish is called when a job finishes running, with the time it took.
+	OnFinish func(time.Duration)
+	// OnError is called when a job of an ErrorPool or ContextPool returns an error, including a *PanicError.
+	OnError func(error)
+	// OnPanic is called when a job panics.
+	OnPanic func(*PanicError)
+	// OnWorkerStart is called when a worker goroutine starts.
+	OnWorkerStart func()
+	// OnWorkerStop is called when a worker goroutine exits.
+	OnWorkerStop func()
+}
+
+// WithHooks attaches lifecycle hooks to the pool.
+//
+// The option can be used several times; the hooks are called in the order they were attached.
+func WithHooks(h Hooks) OptionPool {
+	return func(p *Pool) {
+		p.hooks = p.hooks.join(h)
+	}
+}
+
+// join returns hooks that call the functions of h followed by the functions of o.
+func (h Hooks) join(o Hooks) Hooks {
+	return Hooks{
+		OnSubmit:      join(h.OnSubmit, o.OnSubmit),
+		OnStart:       join(h.OnStart, o.OnStart),
+		OnFinish:      join1(h.OnFinish, o.OnFinish),
+		OnError:       join1(h.OnError, o.OnError),
+		OnPanic:       join1(h.OnPanic, o.OnPanic),
+		OnWorkerStart: join(h.OnWorkerStart, o.OnWorkerStart),
+		OnWorkerStop:  join(h.OnWorkerStop, o.OnWorkerStop),
+	}
+}
+
+// failed reports the error of a failed job to the OnError hook, and to the OnPanic hook if the job panicked.
+func (h Hooks) failed(err error) {
+	if err == nil {
+		return
+	}
+
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		call1(h.OnPanic, pe)
+	}
+	call1(h.OnError, err)
+}
+
+func join(a, b func()) func() {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return func() {
+		a()
+		b()
+	}
+}
+
+func join1[T any](a, b func(T)) func(T) {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return func(v T) {
+		a(v)
+		b(v)
+	}
+}
+
+func call(f func()) {
+	if f != nil {
+		f()
+	}
+}
+
+func call1[T any](f func(T), v T) {
+	if f != nil {
+		f(v)
+	}
+}
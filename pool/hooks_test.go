@@ -0,0 +1,120 @@
+package pool_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("calls hooks for jobs and workers", func(t *testing.T) {
+		t.Parallel()
+
+		var submitted, started, finished, panicked, workersStarted, workersStopped atomic.Int64
+		var slow atomic.Int64
+
+		p := pool.New(3, pool.WithHooks(pool.Hooks{
+			OnSubmit: func() { submitted.Add(1) },
+			OnStart:  func() { started.Add(1) },
+			OnFinish: func(d time.Duration) {
+				finished.Add(1)
+				if d >= 2*time.Millisecond {
+					slow.Add(1)
+				}
+			},
+			OnPanic:       func(pe *pool.PanicError) { panicked.Add(1) },
+			OnWorkerStart: func() { workersStarted.Add(1) },
+			OnWorkerStop:  func() { workersStopped.Add(1) },
+		}))
+
+		for i := 0; i < 10; i++ {
+			p.Go(func() {
+				time.Sleep(2 * time.Millisecond)
+			})
+		}
+		p.Go(func() { panic("intentional panic") })
+
+		recoverPanicError(t, p.Wait)
+
+		if submitted.Load() != 11 || started.Load() != 11 || finished.Load() != 11 {
+			t.Errorf("Expected 11 submitted, started and finished jobs, got %d, %d and %d", submitted.Load(), started.Load(), finished.Load())
+		}
+		if slow.Load() != 10 {
+			t.Errorf("Expected 10 jobs to report their duration, got %d", slow.Load())
+		}
+		if panicked.Load() != 1 {
+			t.Errorf("Expected 1 panicked job, got %d", panicked.Load())
+		}
+		if workersStarted.Load() != 3 || workersStopped.Load() != 3 {
+			t.Errorf("Expected 3 started and stopped workers, got %d and %d", workersStarted.Load(), workersStopped.Load())
+		}
+	})
+
+	t.Run("calls error hooks of a ContextPool", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var errs []error
+		var panicked atomic.Int64
+
+		p := pool.New(2, pool.WithHooks(pool.Hooks{
+			OnError: func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			},
+			OnPanic: func(pe *pool.PanicError) { panicked.Add(1) },
+		})).WithErrors().WithContext(context.Background())
+
+		p.Go(func(c context.Context) error { return fmt.Errorf("intentional error") })
+		p.Go(func(c context.Context) error { panic("intentional panic") })
+		p.Go(func(c context.Context) error { return nil })
+		p.Wait()
+
+		if len(errs) != 2 {
+			t.Errorf("Expected 2 errors reported to the hook, got %d", len(errs))
+		}
+		if panicked.Load() != 1 {
+			t.Errorf("Expected 1 panic reported to the hook, got %d", panicked.Load())
+		}
+	})
+
+	t.Run("composes several hooks in order", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var calls []string
+		record := func(s string) func() {
+			return func() {
+				mu.Lock()
+				calls = append(calls, s)
+				mu.Unlock()
+			}
+		}
+
+		p := pool.New(1,
+			pool.WithHooks(pool.Hooks{OnStart: record("first")}),
+			pool.WithHooks(pool.Hooks{OnFinish: func(time.Duration) { record("finish")() }}),
+			pool.WithHooks(pool.Hooks{OnStart: record("second")}),
+		)
+		p.Go(func() {})
+		p.Wait()
+
+		expected := []string{"first", "second", "finish"}
+		if len(calls) != len(expected) {
+			t.Fatalf("Expected calls %v, got %v", expected, calls)
+		}
+		for i, v := range expected {
+			if calls[i] != v {
+				t.Errorf("Expected call %s at %d, got %s", v, i, calls[i])
+			}
+		}
+	})
+}
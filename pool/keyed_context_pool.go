@@ -0,0 +1,57 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// KeyedContextPool extends ContextPool to run jobs that share a key one at a time, in the order they were submitted.
+//
+// A new keyed context pool must be created using WithKeys(New()).WithErrors().WithContext(). Jobs can be submitted using Go().
+// The keyed context pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected errors.
+type KeyedContextPool[K comparable] struct {
+	contextPool *ContextPool
+
+	keys *keys[K]
+}
+
+// Go submits a job with the given key to the keyed context pool.
+//
+// The job will not run concurrently with other jobs of the same key, and will run after all of them that were submitted before it.
+// Once the context is cancelled, the remaining jobs of every key still run, receiving the cancelled context, so they can return early.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError, which also cancels the context when WithCancelOnErr() is set.
+func (p *KeyedContextPool[K]) Go(key K, job func(context.Context) error) {
+	p.keys.submit(key, p.contextPool.task(job, p.contextPool.jobTimeout, time.Time{}))
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the keyed context pool and stop the goroutine workers.
+func (p *KeyedContextPool[K]) Collect() []error {
+	return p.contextPool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and returns collected errors.
+//
+// After calling Wait(), the keyed context pool is considered closed; new jobs will be dropped.
+func (p *KeyedContextPool[K]) Wait() []error {
+	return p.contextPool.Wait()
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+func (p *KeyedContextPool[K]) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+// Stop closes the keyed context pool like Wait(), but drops the queued jobs, including the jobs waiting for their key, instead of running them,
+// cancels the context of the running jobs, and blocks until they return or ctx is done.
+//
+// Stop() returns the keys of the dropped jobs, one for every dropped job, and ctx.Err() if ctx is done before the running jobs return.
+// The errors of the jobs that did run can be retrieved using Collect() or Wait() afterwards.
+func (p *KeyedContextPool[K]) Stop(ctx context.Context) ([]K, error) {
+	tasks, err := p.contextPool.stop(ctx)
+	return p.keys.stopped(tasks), err
+}
@@ -0,0 +1,66 @@
+package pool
+
+import (
+	"context"
+	"errors"
+)
+
+// KeyedErrorPool extends ErrorPool to run jobs that share a key one at a time, in the order they were submitted.
+//
+// A new keyed error pool must be created using WithKeys(New()).WithErrors(). Jobs can be submitted using Go().
+// The keyed error pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected errors.
+type KeyedErrorPool[K comparable] struct {
+	errorPool *ErrorPool
+
+	keys *keys[K]
+}
+
+// Go submits a job with the given key to the keyed error pool.
+//
+// The job will not run concurrently with other jobs of the same key, and will run after all of them that were submitted before it.
+// A failed job does not stop the following jobs of the same key.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError.
+func (p *KeyedErrorPool[K]) Go(key K, job func() error) {
+	p.keys.submit(key, p.errorPool.task(job))
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the keyed error pool and stop the goroutine workers.
+func (p *KeyedErrorPool[K]) Collect() []error {
+	return p.errorPool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and returns collected errors.
+//
+// After calling Wait(), the keyed error pool is considered closed; new jobs will be dropped.
+func (p *KeyedErrorPool[K]) Wait() []error {
+	return p.errorPool.Wait()
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+func (p *KeyedErrorPool[K]) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+// Stop closes the keyed error pool like Wait(), but drops the queued jobs, including the jobs waiting for their key, instead of running them,
+// and blocks until the running jobs finish or ctx is done.
+//
+// Stop() returns the keys of the dropped jobs, one for every dropped job, and ctx.Err() if ctx is done before the running jobs finish.
+// The errors of the jobs that did run can be retrieved using Collect() or Wait() afterwards.
+func (p *KeyedErrorPool[K]) Stop(ctx context.Context) ([]K, error) {
+	tasks, err := p.errorPool.pool.stop(ctx)
+	return p.keys.stopped(tasks), err
+}
+
+// WithContext converts the KeyedErrorPool to a KeyedContextPool
+//
+// KeyedContextPool accepts jobs along with a key of type K that expect a ctx.context as a parameter and can return errors.
+func (p *KeyedErrorPool[K]) WithContext(ctx context.Context, opts ...OptionCtx) *KeyedContextPool[K] {
+	return &KeyedContextPool[K]{
+		contextPool: p.errorPool.WithContext(ctx, opts...),
+		keys:        p.keys,
+	}
+}
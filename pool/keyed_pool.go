@@ -0,0 +1,157 @@
+package pool
+
+import "context"
+
+// KeyedPool extends Pool to run jobs that share a key one at a time, in the order they were submitted.
+//
+// Jobs with different keys run concurrently on the workers of the pool. A job whose key is busy waits without occupying a worker,
+// so a busy key keeps at most a single worker occupied rather than holding up the others. Waiting jobs do not count towards
+// the queue size and the weight capacity of the pool until their key is free, at which point they are queued like any other job.
+//
+// A new keyed pool must be created using WithKeys(New()). Jobs can be submitted using Go().
+// The keyed pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete.
+type KeyedPool[K comparable] struct {
+	pool *Pool
+
+	keys *keys[K]
+}
+
+// WithKeys converts the Pool to a KeyedPool.
+//
+// KeyedPool accepts jobs along with a key of type K. A key is only tracked while it has a job running or waiting,
+// so using many distinct keys does not grow the memory of the pool.
+func WithKeys[K comparable](p *Pool) *KeyedPool[K] {
+	return &KeyedPool[K]{
+		pool: p,
+		keys: newKeys[K](p),
+	}
+}
+
+// Go submits a job with the given key to the keyed pool.
+//
+// The job will not run concurrently with other jobs of the same key, and will run after all of them that were submitted before it.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is re-raised by Wait() or Collect(), and the next job of the same key runs as usual.
+func (p *KeyedPool[K]) Go(key K, job func()) {
+	p.keys.submit(key, &task{fn: job})
+}
+
+// Collect blocks until all submitted jobs are finished.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the keyed pool and stop the goroutine workers.
+func (p *KeyedPool[K]) Collect() {
+	p.pool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs.
+//
+// After calling Wait(), the keyed pool is considered closed; new jobs will be dropped.
+func (p *KeyedPool[K]) Wait() {
+	p.pool.Wait()
+}
+
+// Stop closes the keyed pool like Wait(), but drops the queued jobs, including the jobs waiting for their key, instead of running them,
+// and blocks until the running jobs finish or ctx is done.
+//
+// Stop() returns the keys of the dropped jobs, one for every dropped job, and ctx.Err() if ctx is done before the running jobs finish.
+// See Pool.Stop() for details.
+func (p *KeyedPool[K]) Stop(ctx context.Context) ([]K, error) {
+	tasks, err := p.pool.stop(ctx)
+	return p.keys.stopped(tasks), err
+}
+
+// WithErrors converts the KeyedPool to a KeyedErrorPool
+//
+// KeyedErrorPool accepts jobs along with a key of type K that can return errors.
+func (p *KeyedPool[K]) WithErrors(opts ...Option) *KeyedErrorPool[K] {
+	return &KeyedErrorPool[K]{
+		errorPool: p.pool.WithErrors(opts...),
+		keys:      p.keys,
+	}
+}
+
+// keys serializes jobs by key on top of a pool.
+//
+// Every job goes through the admission of the pool like any other job. The first job of an idle key is queued right away,
+// while the jobs submitted while the key is busy are held back, without taking room or weight in the pool,
+// and queued one by one as the jobs before them finish. The held jobs are guarded by the mutex of the pool.
+type keys[K comparable] struct {
+	pool *Pool
+
+	queues map[K][]*task
+}
+
+func newKeys[K comparable](p *Pool) *keys[K] {
+	k := &keys[K]{
+		pool:   p,
+		queues: make(map[K][]*task),
+	}
+
+	p.mu.Lock()
+	p.backlogs = append(p.backlogs, k.backlog)
+	p.mu.Unlock()
+
+	return k
+}
+
+func (k *keys[K]) submit(key K, t *task) {
+	t.weight = 1
+	t.key = key
+	t.hold = func() bool { return k.hold(key, t) }
+	t.done = func() { k.release(key) }
+	t.drop = func(error) { k.release(key) }
+
+	_ = k.pool.submit(context.Background(), t, true)
+}
+
+// hold marks the key as busy, and reports whether the task has to be held back because the key was busy already.
+// It must be called with the mutex of the pool held.
+func (k *keys[K]) hold(key K, t *task) bool {
+	q, busy := k.queues[key]
+	if !busy {
+		k.queues[key] = nil
+		return false
+	}
+
+	k.queues[key] = append(q, t)
+	return true
+}
+
+// backlog returns the held tasks of all keys and forgets them. It must be called with the mutex of the pool held.
+func (k *keys[K]) backlog() []*task {
+	var tasks []*task
+	for key, q := range k.queues {
+		tasks = append(tasks, q...)
+		k.queues[key] = nil
+	}
+	return tasks
+}
+
+// stopped returns the keys of the tasks dropped by the pool.
+func (k *keys[K]) stopped(tasks []*task) []K {
+	var dropped []K
+	for _, t := range tasks {
+		if key, ok := t.key.(K); ok {
+			dropped = append(dropped, key)
+		}
+	}
+	return dropped
+}
+
+// release queues the next held task of the key, if any, or marks the key as idle.
+func (k *keys[K]) release(key K) {
+	k.pool.mu.Lock()
+	defer k.pool.mu.Unlock()
+
+	q := k.queues[key]
+	if len(q) == 0 {
+		delete(k.queues, key)
+		return
+	}
+
+	t := q[0]
+	q[0] = nil
+	k.queues[key] = q[1:]
+	k.pool.unhold(t)
+}
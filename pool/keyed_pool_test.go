@@ -0,0 +1,215 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestKeyedPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs jobs of a key serially in order", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[int](pool.New(4))
+		keyCount, jobCount := 5, 40
+
+		var mu sync.Mutex
+		seen := make(map[int][]int)
+		running := make([]atomic.Int32, keyCount)
+		for i := 0; i < jobCount; i++ {
+			key := i % keyCount
+			p.Go(key, func() {
+				if running[key].Add(1) != 1 {
+					t.Errorf("Expected jobs of key %d to run one at a time", key)
+				}
+				time.Sleep(100 * time.Microsecond)
+				mu.Lock()
+				seen[key] = append(seen[key], i)
+				mu.Unlock()
+				running[key].Add(-1)
+			})
+		}
+		p.Wait()
+
+		for key := 0; key < keyCount; key++ {
+			if len(seen[key]) != jobCount/keyCount {
+				t.Fatalf("Expected %d jobs for key %d, got %d", jobCount/keyCount, key, len(seen[key]))
+			}
+			for j, v := range seen[key] {
+				if v != key+j*keyCount {
+					t.Errorf("Expected job %d of key %d, got %d", key+j*keyCount, key, v)
+				}
+			}
+		}
+	})
+
+	t.Run("runs different keys in parallel", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[string](pool.New(2))
+		started := make(chan struct{}, 2)
+		release := make(chan struct{})
+
+		for _, key := range []string{"a", "b"} {
+			p.Go(key, func() {
+				started <- struct{}{}
+				<-release
+			})
+		}
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-started:
+			case <-time.After(time.Second):
+				t.Fatal("Expected jobs of different keys to run in parallel")
+			}
+		}
+		close(release)
+		p.Wait()
+	})
+
+	t.Run("does not let a busy key hold up the others", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[string](pool.New(4))
+		release := make(chan struct{})
+
+		for range 10 {
+			go p.Go("a", func() { <-release })
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		started := make(chan struct{})
+		go p.Go("b", func() { close(started) })
+
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the job of another key to start while the busy key has a backlog")
+		}
+
+		close(release)
+		p.Wait()
+	})
+
+	t.Run("re-raises panics and keeps running the key", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[int](pool.New(2))
+		var ran atomic.Bool
+
+		p.Go(1, func() { panic("boom") })
+		p.Go(1, func() { ran.Store(true) })
+
+		pe := recoverPanicError(t, p.Wait)
+		if pe.Value != "boom" {
+			t.Errorf("Expected panic value boom, got %v", pe.Value)
+		}
+		if !ran.Load() {
+			t.Error("Expected the next job of the key to run after a panic")
+		}
+	})
+
+	t.Run("drops jobs after wait", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[int](pool.New(2))
+		p.Wait()
+
+		var ran atomic.Bool
+		p.Go(1, func() { ran.Store(true) })
+		if ran.Load() {
+			t.Error("Expected job to be dropped after Wait")
+		}
+	})
+
+	t.Run("collects errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[int](pool.New(3)).WithErrors()
+		errTest := errors.New("test")
+
+		for i := 0; i < 20; i++ {
+			p.Go(i%4, func() error {
+				if i%2 == 0 {
+					return errTest
+				}
+				return nil
+			})
+		}
+
+		errs := p.Wait()
+		if len(errs) != 10 {
+			t.Errorf("Expected 10 errors, got %d", len(errs))
+		}
+	})
+
+	t.Run("cancels context on error", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[int](pool.New(2)).WithErrors().WithContext(context.Background(), pool.WithCancelOnErr())
+		errTest := errors.New("test")
+
+		p.Go(1, func(ctx context.Context) error {
+			return errTest
+		})
+		p.Go(1, func(ctx context.Context) error {
+			return ctx.Err()
+		})
+
+		err := p.WaitErr()
+		if !errors.Is(err, errTest) {
+			t.Errorf("Expected error %v, got %v", errTest, err)
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the following job of the key to see a cancelled context, got %v", err)
+		}
+	})
+
+	t.Run("applies pool limits, hooks and stats to waiting jobs", func(t *testing.T) {
+		t.Parallel()
+
+		var started atomic.Int64
+		p := pool.New(1, pool.WithQueueSize(1), pool.WithHooks(pool.Hooks{
+			OnStart: func() { started.Add(1) },
+		}))
+		kp := pool.WithKeys[int](p)
+		release := make(chan struct{})
+
+		kp.Go(1, func() { <-release })
+		kp.Go(2, func() {})
+
+		submitted := make(chan struct{})
+		go func() {
+			kp.Go(3, func() {})
+			close(submitted)
+		}()
+
+		select {
+		case <-submitted:
+			t.Fatal("Expected Go to block while the queue is full")
+		case <-time.After(10 * time.Millisecond):
+		}
+		if s := p.Stats(); s.Queued != 1 || s.Running != 1 {
+			t.Errorf("Expected 1 queued and 1 running job, got %d and %d", s.Queued, s.Running)
+		}
+
+		close(release)
+		<-submitted
+		kp.Wait()
+
+		if s := p.Stats(); s.Submitted != 3 || s.Completed != 3 {
+			t.Errorf("Expected 3 submitted and completed jobs, got %d and %d", s.Submitted, s.Completed)
+		}
+		if started.Load() != 3 {
+			t.Errorf("Expected OnStart to be called 3 times, got %d", started.Load())
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package pool
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError holds the value recovered from a panicking job along with the stack trace of the goroutine that panicked.
+//
+// A Pool re-panics with a *PanicError on the caller's goroutine in Wait() or Collect().
+// An ErrorPool and a ContextPool return it as one of the collected errors instead.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error returns the recovered value followed by the stack trace of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the recovered value if it is an error, allowing errors.Is() and errors.As() to inspect it.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// catch runs the job and returns a *PanicError if it panicked, or nil otherwise.
+func catch(job func()) (pe *PanicError) {
+	defer func() {
+		if r := recover(); r != nil {
+			pe = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	job()
+	return nil
+}
+
+// catchErr runs the job and returns its error, or a *PanicError if it panicked.
+func catchErr(job func() error) error {
+	var err error
+	if pe := catch(func() { err = job() }); pe != nil {
+		return pe
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestPanic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Pool re-panics on Wait and keeps workers alive", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2)
+		jobCount := 20
+		var completed atomic.Int64
+
+		for i := 0; i < jobCount; i++ {
+			p.Go(func() {
+				time.Sleep(1 * time.Millisecond)
+				if i == 3 {
+					panic("intentional panic")
+				}
+				completed.Add(1)
+			})
+		}
+
+		pe := recoverPanicError(t, p.Wait)
+		if pe.Value != "intentional panic" {
+			t.Errorf("Expected panic value 'intentional panic', got: %v", pe.Value)
+		}
+		if len(pe.Stack) == 0 {
+			t.Errorf("Expected stack trace to be captured")
+		}
+		if completed.Load() != int64(jobCount-1) {
+			t.Errorf("Jobs expected: %d, got: %d", jobCount-1, completed.Load())
+		}
+	})
+
+	t.Run("Pool re-panics on Collect and stays usable", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(3)
+		p.Go(func() { panic("intentional panic") })
+
+		recoverPanicError(t, p.Collect)
+
+		var completed atomic.Int64
+		for i := 0; i < 10; i++ {
+			p.Go(func() { completed.Add(1) })
+		}
+
+		p.Wait()
+		if completed.Load() != 10 {
+			t.Errorf("Jobs expected: %d, got: %d", 10, completed.Load())
+		}
+	})
+
+	t.Run("ErrorPool returns panics as errors", func(t *testing.T) {
+		t.Parallel()
+
+		sentinel := errors.New("sentinel")
+		p := pool.New(4).WithErrors()
+
+		for i := 0; i < 10; i++ {
+			p.Go(func() error {
+				switch i {
+				case 2:
+					panic(sentinel)
+				case 5:
+					return fmt.Errorf("err%d", i)
+				}
+				return nil
+			})
+		}
+
+		errs := p.Wait()
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got: %d", len(errs))
+		}
+
+		var panics int
+		for _, err := range errs {
+			var pe *pool.PanicError
+			if errors.As(err, &pe) {
+				panics++
+				if !errors.Is(err, sentinel) {
+					t.Errorf("Expected panic error to unwrap to the panic value, got: %v", err)
+				}
+				if !strings.Contains(err.Error(), "sentinel") {
+					t.Errorf("Expected panic error message to contain the panic value, got: %s", err.Error())
+				}
+			}
+		}
+		if panics != 1 {
+			t.Errorf("Expected 1 panic error, got: %d", panics)
+		}
+	})
+
+	t.Run("ContextPool cancels on panic", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2).WithErrors(pool.WithOnlyFirstErr()).WithContext(context.Background(), pool.WithCancelOnErr())
+
+		p.Go(func(c context.Context) error {
+			panic("intentional panic")
+		})
+		p.Go(func(c context.Context) error {
+			select {
+			case <-c.Done():
+				return c.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		})
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected only first error, got: %d", len(errs))
+		}
+
+		var pe *pool.PanicError
+		if !errors.As(errs[0], &pe) {
+			t.Errorf("Expected *pool.PanicError, got: %v", errs[0])
+		}
+	})
+}
+
+func recoverPanicError(t *testing.T, fn func()) (pe *pool.PanicError) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("Expected a panic, got none")
+		}
+
+		var ok bool
+		pe, ok = r.(*pool.PanicError)
+		if !ok {
+			t.Fatalf("Expected *pool.PanicError, got: %T", r)
+		}
+	}()
+
+	fn()
+	return nil
+}
@@ -0,0 +1,162 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestPause(t *testing.T) {
+	t.Parallel()
+
+	t.Run("holds queued jobs until Resume", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(10))
+		p.Pause()
+		if !p.Paused() {
+			t.Fatal("Expected pool to be paused")
+		}
+
+		var completed atomic.Int64
+		for i := 0; i < 10; i++ {
+			p.Go(func() { completed.Add(1) })
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		if completed.Load() != 0 {
+			t.Errorf("Expected 0 completed jobs while paused, got %d", completed.Load())
+		}
+		if s := p.Stats(); s.Queued != 10 {
+			t.Errorf("Expected 10 queued jobs while paused, got %d", s.Queued)
+		}
+
+		p.Resume()
+		if p.Paused() {
+			t.Error("Expected pool not to be paused after Resume")
+		}
+		p.Collect()
+		if completed.Load() != 10 {
+			t.Errorf("Expected 10 completed jobs, got %d", completed.Load())
+		}
+		p.Wait()
+	})
+
+	t.Run("Collect waits only for running jobs while paused", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(5))
+		started := make(chan struct{})
+		var completed atomic.Int64
+
+		p.Go(func() {
+			close(started)
+			time.Sleep(5 * time.Millisecond)
+			completed.Add(1)
+		})
+		<-started
+		p.Pause()
+		for i := 0; i < 5; i++ {
+			p.Go(func() { completed.Add(1) })
+		}
+
+		p.Collect()
+		if completed.Load() != 1 {
+			t.Errorf("Expected 1 completed job after Collect while paused, got %d", completed.Load())
+		}
+
+		p.Resume()
+		p.Collect()
+		if completed.Load() != 6 {
+			t.Errorf("Expected 6 completed jobs, got %d", completed.Load())
+		}
+		p.Wait()
+	})
+
+	t.Run("Wait resumes a paused pool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(5))
+		p.Pause()
+
+		var completed atomic.Int64
+		for i := 0; i < 5; i++ {
+			p.Go(func() { completed.Add(1) })
+		}
+
+		p.Wait()
+		if completed.Load() != 5 {
+			t.Errorf("Expected 5 completed jobs, got %d", completed.Load())
+		}
+		if p.Paused() {
+			t.Error("Expected pool not to be paused after Wait")
+		}
+	})
+
+	t.Run("propagates to ContextPool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(5)).WithErrors().WithContext(context.Background())
+		errTest := errors.New("test")
+		p.Pause()
+		if !p.Paused() {
+			t.Fatal("Expected context pool to be paused")
+		}
+
+		for i := 0; i < 5; i++ {
+			p.Go(func(ctx context.Context) error { return errTest })
+		}
+
+		if errs := p.Collect(); errs != nil {
+			t.Errorf("Expected no errors while paused, got %v", errs)
+		}
+
+		p.Resume()
+		if errs := p.Collect(); len(errs) != 5 {
+			t.Errorf("Expected 5 errors, got %d", len(errs))
+		}
+		p.Wait()
+	})
+
+	t.Run("holds jobs waiting for their key", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(10))
+		kp := pool.WithKeys[int](p)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var completed atomic.Int64
+
+		kp.Go(1, func() {
+			close(started)
+			<-release
+			completed.Add(1)
+		})
+		for i := 0; i < 5; i++ {
+			kp.Go(1, func() { completed.Add(1) })
+		}
+
+		<-started
+		p.Pause()
+		close(release)
+		kp.Collect()
+
+		time.Sleep(5 * time.Millisecond)
+		if completed.Load() != 1 {
+			t.Errorf("Expected 1 completed job while paused, got %d", completed.Load())
+		}
+		if s := p.Stats(); s.Queued != 5 {
+			t.Errorf("Expected 5 queued jobs while paused, got %d", s.Queued)
+		}
+
+		p.Resume()
+		kp.Wait()
+		if completed.Load() != 6 {
+			t.Errorf("Expected 6 completed jobs, got %d", completed.Load())
+		}
+	})
+}
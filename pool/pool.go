@@ -1,10 +1,21 @@
 package pool
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/kiriyms/conpats/ratelimit"
 )
 
+// ErrClosed is returned when a job is submitted to a pool after Wait() has been called.
+var ErrClosed = errors.New("pool: closed")
+
+var errQueueFull = errors.New("pool: queue full")
+
 type Option func(*ErrorPool)
 
 // WithOnlyFirstErr allows specifying if an Error Pool will only return the first error.
@@ -14,100 +25,383 @@ func WithOnlyFirstErr() Option {
 	}
 }
 
-// Pool manages a fixed number of workers executing jobs.
+type OptionPool func(*Pool)
+
+// WithRateLimit makes the workers of the pool wait for a token of a token bucket rate limiter before starting each job,
+// so that at most rate jobs per second are started on average, with bursts of up to burst jobs.
+//
+// Jobs of a Context Pool stop waiting once the pool context is cancelled, and run right away with the cancelled context.
+// A rate of 0 or less leaves the pool without a rate limit. By default, jobs are started as soon as a worker picks them up.
+func WithRateLimit(rate float64, burst int) OptionPool {
+	if rate <= 0 {
+		return func(*Pool) {}
+	}
+	return WithLimiter(ratelimit.New(rate, burst))
+}
+
+// WithLimiter is like WithRateLimit(), but uses the given rate limiter, which can be shared with other pools or pipes.
 //
-// A new pool must be created using New(). Jobs can be submitted using Go() or TryGo().
+// A limiter created with a rate of 0 or less never refills, so once its first burst jobs have started,
+// the other jobs wait until their context is cancelled, and jobs without a context wait forever.
+func WithLimiter(l *ratelimit.Limiter) OptionPool {
+	return func(p *Pool) {
+		p.limiter = l
+	}
+}
+
+// WithWeightCapacity makes the pool limit the total weight of its queued and running jobs to capacity,
+// on top of the limit on the number of jobs set by the workers and WithQueueSize().
+//
+// Jobs submitted using GoWeighted() or TryGoWeighted() declare their own weight, all other jobs weigh 1.
+// A job weighing more than capacity is admitted once the pool has no other job in flight, so that it can still run.
+// By default, jobs are not weighed.
+func WithWeightCapacity(capacity int) OptionPool {
+	return func(p *Pool) {
+		p.capacity = max(capacity, 0)
+	}
+}
+
+// WithQueueSize sets how many jobs can wait in the queue when all workers are busy.
+// By default, the queue size is 0 and submitting a job blocks until a worker is free.
+func WithQueueSize(size int) OptionPool {
+	return func(p *Pool) {
+		p.queueSize = max(size, 0)
+	}
+}
+
+// WithPriorityAging makes the priority of a queued job grow by one for every period it spends waiting in the queue,
+// so that jobs submitted with a low priority using GoPriority() are not starved by a steady flow of high-priority jobs.
+// By default, priorities do not change while jobs wait.
+func WithPriorityAging(period time.Duration) OptionPool {
+	return func(p *Pool) {
+		p.queue.aging = period
+	}
+}
+
+// WithElastic makes the pool spawn its workers on demand, up to the number of workers given to New().
+//
+// Workers above minWorkers exit after being idle for idleTimeout, and are spawned again when submitted jobs would otherwise wait.
+// By default, all workers are spawned immediately and live until Wait() is called.
+func WithElastic(minWorkers int, idleTimeout time.Duration) OptionPool {
+	return func(p *Pool) {
+		p.elastic = true
+		p.minWorkers = max(minWorkers, 0)
+		p.idleTimeout = idleTimeout
+	}
+}
+
+// Pool manages a number of workers executing jobs.
+//
+// A new pool must be created using New(). Jobs can be submitted using Go(), TryGo() or GoContext().
 // The pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete.
 type Pool struct {
-	workers int
-	jobs    chan func()
+	workers   int
+	queueSize int
+	capacity  int
+
+	elastic     bool
+	minWorkers  int
+	idleTimeout time.Duration
+
+	limiter *ratelimit.Limiter
+
+	stateInit    func() any
+	stateCleanup func(any)
 
-	activeWg sync.WaitGroup
-	wg       sync.WaitGroup
+	// backlogs return and forget the held tasks of the keyed pools built on top of the pool. They must be called with mu held.
+	backlogs []func() []*task
 
-	once   sync.Once
-	closed atomic.Bool
+	mu      sync.Mutex
+	queue   queue
+	pending int
+	held    int
+	retries map[*task]struct{}
+	weight  int
+	live    int
+	idle    []*worker
+	room    chan struct{}
+	paused  bool
+	closed  bool
+
+	wg sync.WaitGroup
+
+	panicked atomic.Pointer[PanicError]
+	stats    stats
+	hooks    Hooks
+}
+
+// worker is a goroutine of the pool, which sleeps on wake while there are no jobs in the queue.
+//
+// A worker of a WorkerPool also holds its state, which is created by the first stateful job it runs.
+type worker struct {
+	wake chan struct{}
+
+	state any
+	ready bool
 }
 
 // New creates a new Pool and immediately spawns all its worker goroutines.
-func New(workers int) *Pool {
+//
+// If the pool is elastic (see WithElastic()), only the minimum number of workers is spawned immediately.
+func New(workers int, opts ...OptionPool) *Pool {
 	if workers <= 0 {
 		workers = 1
 	}
 
 	p := &Pool{
 		workers: workers,
-		jobs:    make(chan func()),
+		queue:   queue{start: time.Now()},
 	}
 
-	for i := 0; i < p.workers; i++ {
-		p.wg.Add(1)
-		go func() {
-			defer p.wg.Done()
-			for job := range p.jobs {
-				job()
-			}
-		}()
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.mu.Lock()
+	for p.live < p.baseWorkers() {
+		p.spawn()
 	}
+	p.mu.Unlock()
 
 	return p
 }
 
 // Go submits a job to the pool.
 //
+// Go blocks while all workers are busy and the queue is full.
 // If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the worker recovers and keeps running; the panic is re-raised by Wait() or Collect().
 func (p *Pool) Go(job func()) {
-	closed := p.closed.Load()
+	_ = p.submit(context.Background(), &task{fn: job, weight: 1}, true)
+}
 
-	if closed {
-		return
-	}
+// GoPriority submits a job with the given priority to the pool.
+//
+// Queued jobs with a higher priority are picked up by workers first; jobs submitted using Go() have a priority of 0.
+// Jobs of the same priority are picked up in the order they were submitted. See WithPriorityAging() to avoid starving low-priority jobs.
+// GoPriority blocks while all workers are busy and the queue is full.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *Pool) GoPriority(priority int, job func()) {
+	_ = p.submit(context.Background(), &task{fn: job, priority: priority, weight: 1}, true)
+}
 
-	p.activeWg.Add(1)
-	p.jobs <- func() {
-		defer p.activeWg.Done()
-		job()
-	}
+// GoWithID submits a job to the pool along with an identifier, which is returned by Stop() if the job is dropped.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *Pool) GoWithID(id string, job func()) {
+	_ = p.submit(context.Background(), &task{fn: job, weight: 1, id: id}, true)
 }
 
-// TryGo attempts to submit a job to the pool.
+// TryGo attempts to submit a job to the pool without blocking.
 //
-// If a job is submitted after Wait() has been called, it will be dropped and false is returned.
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
 // Otherwise, true is returned.
 func (p *Pool) TryGo(job func()) bool {
-	closed := p.closed.Load()
+	return p.submit(context.Background(), &task{fn: job, weight: 1}, false) == nil
+}
 
-	if closed {
-		return false
-	}
+// GoWeighted submits a job with the given weight to the pool.
+//
+// GoWeighted blocks while all workers are busy and the queue is full, or while admitting the job would exceed the capacity
+// set by WithWeightCapacity(). Weights below 1 count as 1. Without WithWeightCapacity(), the weight is ignored.
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *Pool) GoWeighted(weight int, job func()) {
+	_ = p.submit(context.Background(), &task{fn: job, weight: weight}, true)
+}
 
-	p.activeWg.Add(1)
-	p.jobs <- func() {
-		defer p.activeWg.Done()
-		job()
-	}
+// TryGoWeighted attempts to submit a job with the given weight to the pool without blocking.
+//
+// If a job is submitted after Wait() has been called, all workers are busy and the queue is full, or admitting the job
+// would exceed the capacity set by WithWeightCapacity(), it will be dropped and false is returned. Otherwise, true is returned.
+func (p *Pool) TryGoWeighted(weight int, job func()) bool {
+	return p.submit(context.Background(), &task{fn: job, weight: weight}, false) == nil
+}
 
-	return true
+// GoContext submits a job to the pool, waiting for room in the queue until ctx is done.
+//
+// If a job is submitted after Wait() has been called, ErrClosed is returned.
+// If ctx is done before the job could be queued, the job is dropped and ctx.Err() is returned.
+func (p *Pool) GoContext(ctx context.Context, job func()) error {
+	return p.submit(ctx, &task{fn: job, weight: 1}, true)
 }
 
 // Collect blocks until all submitted jobs are finished.
 //
+// While the pool is paused, Collect() only waits for the running jobs to finish, leaving queued jobs for after Resume().
 // This does not prevent new jobs from being submitted after using Collect().
 // Collect() does not close the pool and stop the goroutine workers.
+// If any job panicked since the last Collect(), Collect() re-panics with a *PanicError holding the first recovered panic.
 func (p *Pool) Collect() {
-	p.activeWg.Wait()
+	p.mu.Lock()
+	for (p.pending > 0 || len(p.retries) > 0) && !(p.paused && p.pending == p.queued()) {
+		room := p.awaitRoom()
+		p.mu.Unlock()
+		<-room
+		p.mu.Lock()
+	}
+	p.mu.Unlock()
+
+	p.repanic()
+}
+
+// Pause stops the workers from picking up queued jobs, letting the running jobs finish.
+//
+// Jobs can still be submitted to a paused pool; they wait in the queue until Resume() is called, as long as it has room for them.
+// Jobs of a KeyedPool that were waiting for their key stay queued as well once their key is free.
+// Pausing a paused pool has no effect.
+func (p *Pool) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.paused = true
+		p.notifyRoom()
+	}
+}
+
+// Resume lets the workers of a paused pool pick up queued jobs again. Resuming a pool that is not paused has no effect.
+func (p *Pool) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		p.paused = false
+		p.wakeIdle()
+	}
+}
+
+// Paused reports whether the pool is paused.
+func (p *Pool) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.paused
 }
 
 // Wait closes the job queue and blocks until all workers finish the jobs.
 //
 // After calling Wait(), the pool is considered closed; new jobs will be dropped.
+// Wait() resumes a paused pool, so that its queued jobs are run.
+// If any job panicked, Wait() re-panics with a *PanicError holding the first recovered panic.
 func (p *Pool) Wait() {
-	p.once.Do(func() {
-		p.closed.Store(true)
-		close(p.jobs)
-	})
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		p.paused = false
+		p.wakeIdle()
+		p.notifyRoom()
+	}
+	p.mu.Unlock()
 
 	p.wg.Wait()
+	p.repanic()
+}
+
+// Stop closes the pool like Wait(), but drops the queued jobs instead of running them,
+// and blocks until the running jobs finish or ctx is done.
+//
+// The dropped jobs include the jobs of a KeyedPool waiting for their key, and are also counted as dropped by Stats().
+// Stop() returns the identifiers of the dropped jobs, as given to GoWithID(), with an empty identifier for every dropped job
+// that was submitted without one.
+// If ctx is done before the running jobs finish, ctx.Err() is returned and the jobs are left running in the background;
+// Wait() can still be used to wait for them.
+// If any job panicked and the running jobs finished in time, Stop() re-panics with a *PanicError holding the first recovered panic.
+func (p *Pool) Stop(ctx context.Context) ([]string, error) {
+	tasks, err := p.stop(ctx)
+	return ids(tasks), err
+}
+
+// stop implements Stop(), returning the dropped tasks.
+func (p *Pool) stop(ctx context.Context) ([]*task, error) {
+	p.mu.Lock()
+	p.closed = true
+	p.paused = false
+	tasks := p.queue.tasks
+	p.queue.tasks = nil
+	for _, t := range tasks {
+		p.release(t)
+	}
+	for _, backlog := range p.backlogs {
+		for _, t := range backlog() {
+			p.discard(t)
+			tasks = append(tasks, t)
+		}
+	}
+	for t := range p.retries {
+		p.forget(t)
+		tasks = append(tasks, t)
+	}
+	p.wakeIdle()
+	// wake up the blocked submitters, which drop their jobs now that the pool is closed
+	p.notifyRoom()
+	p.mu.Unlock()
+
+	for _, t := range tasks {
+		if t.drop != nil {
+			t.drop(ErrClosed)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return tasks, ctx.Err()
+	}
+
+	p.repanic()
+	return tasks, nil
+}
+
+// ids returns the identifiers of the tasks.
+func ids(tasks []*task) []string {
+	ids := make([]string, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.id
+	}
+	return ids
+}
+
+// Resize changes the number of workers in the pool.
+//
+// Growing the pool spawns new workers immediately, unless the pool is elastic, in which case workers are spawned on demand.
+// Shrinking the pool lets busy workers finish their current job before they exit.
+// Negative or zero workers are valid, but will resize the pool to 1 worker. Resizing a pool after Wait() has been called has no effect.
+func (p *Pool) Resize(workers int) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	p.workers = workers
+	for p.live < p.baseWorkers() {
+		p.spawn()
+	}
+	if p.live > p.workers {
+		p.wakeIdle()
+	}
+	p.notifyRoom()
+}
+
+// Workers returns the current number of workers in the pool, as set by New() or Resize().
+//
+// For an elastic pool, this is the maximum number of workers.
+func (p *Pool) Workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.workers
 }
 
 // WithErrors converts the Pool to an ErrorPool
@@ -124,3 +418,425 @@ func (p *Pool) WithErrors(opts ...Option) *ErrorPool {
 
 	return &ep
 }
+
+// submit queues the job of the task once there is room for it, or returns an error if the job has to be dropped.
+//
+// If block is false, submit returns errQueueFull instead of waiting for room.
+func (p *Pool) submit(ctx context.Context, t *task, block bool) error {
+	t.weight = p.clampWeight(t.weight)
+
+	p.mu.Lock()
+	for {
+		if p.closed {
+			p.mu.Unlock()
+			p.stats.dropped.Add(1)
+			return ErrClosed
+		}
+		// a job whose ctx is done is dropped even if there is room for it, so that callers shedding load do not queue stale work
+		if err := ctx.Err(); err != nil {
+			p.mu.Unlock()
+			p.stats.rejected.Add(1)
+			return err
+		}
+		if p.admits(t.weight) {
+			break
+		}
+		if !block {
+			p.mu.Unlock()
+			p.stats.rejected.Add(1)
+			return errQueueFull
+		}
+
+		room := p.awaitRoom()
+		p.mu.Unlock()
+
+		select {
+		case <-room:
+		case <-ctx.Done():
+			p.stats.rejected.Add(1)
+			return ctx.Err()
+		}
+
+		p.mu.Lock()
+	}
+
+	p.stats.submitted.Add(1)
+	p.pending++
+	t.run = p.wrap(t)
+	if t.hold != nil && t.hold() {
+		p.held++
+	} else {
+		p.weight += t.weight
+		p.queue.push(t)
+		p.dispatch()
+	}
+	p.mu.Unlock()
+
+	call(p.hooks.OnSubmit)
+	return nil
+}
+
+// unhold queues a task that was held back when it was submitted, which takes its room and weight from then on.
+// It must be called with p.mu held.
+func (p *Pool) unhold(t *task) {
+	p.held--
+	p.weight += t.weight
+	p.queue.push(t)
+	p.dispatch()
+}
+
+// dispatch wakes up an idle worker to pick up a queued job, or spawns a new one if the pool has room for it.
+// It must be called with p.mu held.
+func (p *Pool) dispatch() {
+	if n := len(p.idle); n > 0 {
+		w := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		w.wake <- struct{}{}
+	} else if p.live < p.workers {
+		p.spawn()
+	}
+}
+
+// spawn starts a new worker goroutine. It must be called with p.mu held.
+func (p *Pool) spawn() {
+	w := &worker{wake: make(chan struct{}, 1)}
+
+	p.live++
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		call(p.hooks.OnWorkerStart)
+		defer call(p.hooks.OnWorkerStop)
+		defer p.releaseState(w)
+
+		for {
+			job, ok := p.next(w)
+			if !ok {
+				return
+			}
+
+			job(w)
+		}
+	}()
+}
+
+// next blocks until a job is queued and returns it, or returns false if the worker has to exit.
+// While the pool is paused, queued jobs are left in the queue.
+//
+// A worker exits when the pool has been shrunk below the number of live workers, or when the pool is closed and the queue is empty.
+// A worker of an elastic pool also exits after being idle for the idle timeout, as long as there are more live workers than the minimum,
+// and it is not the last live worker while jobs wait to be retried.
+func (p *Pool) next(w *worker) (func(*worker), bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		if p.live > p.workers {
+			p.live--
+			return nil, false
+		}
+		if p.closed && p.queue.Len() == 0 && len(p.retries) == 0 {
+			// let the idle workers exit too, since the last retried job may have been run by this worker
+			p.live--
+			p.wakeIdle()
+			return nil, false
+		}
+		if p.queue.Len() > 0 && !p.paused {
+			break
+		}
+
+		p.idle = append(p.idle, w)
+		if !p.elastic || p.live <= p.baseWorkers() {
+			p.mu.Unlock()
+			<-w.wake
+			p.mu.Lock()
+			continue
+		}
+
+		timer := time.NewTimer(p.idleTimeout)
+		p.mu.Unlock()
+		select {
+		case <-w.wake:
+			timer.Stop()
+			p.mu.Lock()
+		case <-timer.C:
+			p.mu.Lock()
+			i := slices.Index(p.idle, w)
+			if i < 0 {
+				// the worker has been woken up in the meantime, so drain the wake-up before sleeping again
+				<-w.wake
+				continue
+			}
+
+			p.idle = slices.Delete(p.idle, i, i+1)
+			// keep a worker alive while jobs wait to be retried, so that Wait() does not return before they run
+			if p.live > p.baseWorkers() && (len(p.retries) == 0 || p.live > 1) {
+				p.live--
+				return nil, false
+			}
+		}
+	}
+
+	return p.queue.pop().run, true
+}
+
+// cancel removes the task from the queue if it has not been picked up by a worker yet, or from the tasks waiting to be retried,
+// and reports whether it was removed.
+func (p *Pool) cancel(t *task) bool {
+	p.mu.Lock()
+	ok := p.queue.remove(t)
+	if ok {
+		p.release(t)
+	} else if _, ok = p.retries[t]; ok {
+		p.forget(t)
+		if p.closed {
+			p.wakeIdle()
+		}
+	}
+	p.mu.Unlock()
+
+	if ok && t.drop != nil {
+		t.drop(context.Canceled)
+	}
+	return ok
+}
+
+// release releases the room and the weight taken by a task removed from the queue without being run. It must be called with p.mu held.
+func (p *Pool) release(t *task) {
+	p.pending--
+	p.weight -= t.weight
+	p.stats.dropped.Add(1)
+	p.notifyRoom()
+}
+
+// discard drops a held task, which does not take room or weight until it is queued. It must be called with p.mu held.
+func (p *Pool) discard(t *task) {
+	p.held--
+	p.pending--
+	p.stats.dropped.Add(1)
+	p.notifyRoom()
+}
+
+// forget removes a task waiting to be retried, which does not take room or weight in the meantime. It must be called with p.mu held.
+func (p *Pool) forget(t *task) {
+	delete(p.retries, t)
+	p.stats.dropped.Add(1)
+	p.notifyRoom()
+}
+
+// retry queues the task again once its backoff has passed, or right away once its context is done,
+// without holding a worker, room in the queue or weight in the meantime.
+//
+// The task goes through admission again, but is not counted as submitted again, and is queued even if the pool has been closed
+// by Wait() in the meantime. If the task is removed by Stop() or cancel() while it waits, it is not queued.
+func (p *Pool) retry(t *task) {
+	p.mu.Lock()
+	if p.retries == nil {
+		p.retries = make(map[*task]struct{})
+	}
+	p.retries[t] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		ctx := t.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		timer := time.NewTimer(t.backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		for {
+			if _, ok := p.retries[t]; !ok {
+				return
+			}
+			if p.admits(t.weight) {
+				break
+			}
+
+			room := p.awaitRoom()
+			p.mu.Unlock()
+			<-room
+			p.mu.Lock()
+		}
+
+		delete(p.retries, t)
+		p.pending++
+		p.weight += t.weight
+		p.queue.push(t)
+		p.dispatch()
+	}()
+}
+
+// complete queues the task again if its job is to be retried, or calls its done function otherwise.
+func (p *Pool) complete(t *task) {
+	if t.retrying {
+		t.retrying = false
+		p.retry(t)
+		return
+	}
+
+	if t.done != nil {
+		t.done()
+	}
+}
+
+// finish releases the room and the weight taken by a finished job.
+func (p *Pool) finish(weight int) {
+	p.mu.Lock()
+	p.pending--
+	p.weight -= weight
+	p.notifyRoom()
+	p.mu.Unlock()
+}
+
+// clampWeight returns the weight a job counts for: 0 when jobs are not weighed, or the given weight clamped between 1 and the capacity.
+func (p *Pool) clampWeight(weight int) int {
+	if p.capacity == 0 {
+		return 0
+	}
+	return min(max(weight, 1), p.capacity)
+}
+
+// admits reports whether a job of the given weight can be admitted, given the room left by the workers and the queue
+// and the capacity. Held tasks do not take room, so that a busy key of a KeyedPool cannot hold up the other keys.
+// It must be called with p.mu held.
+func (p *Pool) admits(weight int) bool {
+	return p.pending-p.held < p.workers+p.queueSize && p.fits(weight)
+}
+
+// fits reports whether a job of the given weight can be admitted without exceeding the capacity. It must be called with p.mu held.
+func (p *Pool) fits(weight int) bool {
+	return p.capacity == 0 || p.weight+weight <= p.capacity
+}
+
+// queued returns the number of accepted jobs that are not running yet, including held ones. It must be called with p.mu held.
+func (p *Pool) queued() int {
+	return p.queue.Len() + p.held
+}
+
+// baseWorkers returns the number of workers that are kept alive at all times. It must be called with p.mu held.
+func (p *Pool) baseWorkers() int {
+	if p.elastic {
+		return min(p.minWorkers, p.workers)
+	}
+	return p.workers
+}
+
+// wakeIdle wakes up all idle workers so they can re-check the state of the pool. It must be called with p.mu held.
+func (p *Pool) wakeIdle() {
+	for _, w := range p.idle {
+		w.wake <- struct{}{}
+	}
+	p.idle = nil
+}
+
+// awaitRoom returns a channel that is closed by the next notifyRoom(). It must be called with p.mu held.
+func (p *Pool) awaitRoom() <-chan struct{} {
+	if p.room == nil {
+		p.room = make(chan struct{})
+	}
+	return p.room
+}
+
+// notifyRoom wakes up all submitters waiting for room in the queue, as well as Collect(). It must be called with p.mu held.
+func (p *Pool) notifyRoom() {
+	if p.room != nil {
+		close(p.room)
+		p.room = nil
+	}
+}
+
+// wrap returns the job of the task, which waits for the rate limiter, marks itself as done, releasing its weight,
+// and records its run time and panic, if any.
+func (p *Pool) wrap(t *task) func(*worker) {
+	job := p.job(t)
+	return func(w *worker) {
+		defer p.finish(t.weight)
+		defer p.complete(t)
+
+		p.limit(t.ctx)
+
+		call(p.hooks.OnStart)
+		start := time.Now()
+		pe := catch(func() { job(w) })
+		d := time.Since(start)
+		p.stats.observe(d)
+
+		p.recovered(pe)
+		call1(p.hooks.OnFinish, d)
+	}
+}
+
+// job returns the job of the task, passing it the state of the worker if the task is stateful.
+func (p *Pool) job(t *task) func(*worker) {
+	if t.stateful != nil {
+		stateful := t.stateful
+		return func(w *worker) { stateful(func() any { return p.state(w) }) }
+	}
+
+	fn := t.fn
+	return func(*worker) { fn() }
+}
+
+// state returns the state of the worker, creating it on first use. It must only be called from the worker goroutine,
+// by the job of a task, so that a panic of the init function is caught like one of the job.
+func (p *Pool) state(w *worker) any {
+	if !w.ready && p.stateInit != nil {
+		w.state = p.stateInit()
+		w.ready = true
+	}
+	return w.state
+}
+
+// releaseState cleans up the state of an exiting worker, if it was created, recording a panic of the cleanup function like one of a job.
+func (p *Pool) releaseState(w *worker) {
+	if w.ready && p.stateCleanup != nil {
+		p.recovered(catch(func() { p.stateCleanup(w.state) }))
+	}
+}
+
+// limit blocks until the rate limiter of the pool, if any, allows a job to start, or until ctx is done.
+//
+// The limiter gives up right away on a token that would only arrive after the deadline of ctx, so it waits on a context
+// without the deadline instead, which is cancelled once ctx is done.
+func (p *Pool) limit(ctx context.Context) {
+	if p.limiter == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	wctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	defer context.AfterFunc(ctx, cancel)()
+
+	_ = p.limiter.Wait(wctx)
+}
+
+// recovered records the panic of a job, if any, to be re-raised by Wait() or Collect().
+func (p *Pool) recovered(pe *PanicError) {
+	if pe == nil {
+		return
+	}
+
+	p.stats.fail(pe)
+	call1(p.hooks.OnPanic, pe)
+	p.panicked.CompareAndSwap(nil, pe)
+}
+
+// repanic re-raises the first recovered panic on the caller's goroutine and resets it.
+func (p *Pool) repanic() {
+	if pe := p.panicked.Swap(nil); pe != nil {
+		panic(pe)
+	}
+}
@@ -1,6 +1,8 @@
 package pool_test
 
 import (
+	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -142,4 +144,348 @@ func TestPool(t *testing.T) {
 			t.Errorf("Expected 0 completed jobs, got %d", completed.Load())
 		}
 	})
+	t.Run("TryGo does not block when queue is full", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(2))
+		release := make(chan struct{})
+
+		for i := 0; i < 3; i++ {
+			ok := p.TryGo(func() { <-release })
+			if !ok {
+				t.Fatalf("Expected job %d to be accepted", i)
+			}
+		}
+
+		ok := p.TryGo(func() { <-release })
+		if ok {
+			t.Errorf("Expected .TryGo() to return false when all workers are busy and the queue is full")
+		}
+
+		close(release)
+		p.Collect()
+
+		ok = p.TryGo(func() {})
+		if !ok {
+			t.Errorf("Expected .TryGo() to succeed once the queue has room")
+		}
+
+		p.Wait()
+	})
+
+	t.Run("GoContext waits for room until context is done", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		release := make(chan struct{})
+		p.Go(func() { <-release })
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var completed atomic.Int64
+		err := p.GoContext(ctx, func() { completed.Add(1) })
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+		}
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			close(release)
+		}()
+
+		err = p.GoContext(context.Background(), func() { completed.Add(1) })
+		if err != nil {
+			t.Errorf("Expected job to be submitted once there is room, got: %v", err)
+		}
+
+		p.Wait()
+		if completed.Load() != 1 {
+			t.Errorf("Expected 1 completed job, got %d", completed.Load())
+		}
+
+		err = p.GoContext(context.Background(), func() {})
+		if !errors.Is(err, pool.ErrClosed) {
+			t.Errorf("Expected pool.ErrClosed after Wait, got: %v", err)
+		}
+	})
+
+	t.Run("GoContext drops jobs whose context is done", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var completed atomic.Int64
+		err := p.GoContext(ctx, func() { completed.Add(1) })
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got: %v", err)
+		}
+
+		p.Wait()
+		if completed.Load() != 0 {
+			t.Errorf("Expected the job not to run, got %d completed jobs", completed.Load())
+		}
+		if s := p.Stats(); s.Rejected != 1 || s.Submitted != 0 {
+			t.Errorf("Expected 1 rejected and 0 submitted jobs, got %d and %d", s.Rejected, s.Submitted)
+		}
+	})
+
+	t.Run("unblocks waiting Go on Wait", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		release := make(chan struct{})
+		p.Go(func() { <-release })
+
+		submitted := make(chan error)
+		go func() {
+			submitted <- p.GoContext(context.Background(), func() {})
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		close(release)
+		p.Wait()
+
+		if err := <-submitted; err != nil && !errors.Is(err, pool.ErrClosed) {
+			t.Errorf("Expected nil or pool.ErrClosed, got: %v", err)
+		}
+	})
+	t.Run("grows and shrinks with Resize", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2)
+		if p.Workers() != 2 {
+			t.Fatalf("Expected 2 workers, got %d", p.Workers())
+		}
+
+		var running, peak atomic.Int64
+		job := func() {
+			n := running.Add(1)
+			for {
+				m := peak.Load()
+				if n <= m || peak.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		}
+
+		p.Resize(6)
+		if p.Workers() != 6 {
+			t.Fatalf("Expected 6 workers after Resize, got %d", p.Workers())
+		}
+		for i := 0; i < 30; i++ {
+			p.Go(job)
+		}
+		p.Collect()
+		if peak.Load() != 6 {
+			t.Errorf("Expected 6 jobs running at peak after growing, got %d", peak.Load())
+		}
+
+		p.Resize(1)
+		peak.Store(0)
+		for i := 0; i < 10; i++ {
+			p.Go(job)
+		}
+		p.Wait()
+		if peak.Load() != 1 {
+			t.Errorf("Expected 1 job running at peak after shrinking, got %d", peak.Load())
+		}
+	})
+
+	t.Run("lets busy workers finish on shrink", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4)
+		release := make(chan struct{})
+		var completed atomic.Int64
+
+		for i := 0; i < 4; i++ {
+			p.Go(func() {
+				<-release
+				completed.Add(1)
+			})
+		}
+
+		p.Resize(0)
+		if p.Workers() != 1 {
+			t.Errorf("Expected 1 worker, got %d", p.Workers())
+		}
+
+		close(release)
+		p.Wait()
+		if completed.Load() != 4 {
+			t.Errorf("Expected 4 completed jobs, got %d", completed.Load())
+		}
+	})
+	t.Run("elastic pool spawns workers on demand", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4, pool.WithElastic(0, 5*time.Millisecond))
+		var running, peak, completed atomic.Int64
+
+		for round := 0; round < 2; round++ {
+			for i := 0; i < 20; i++ {
+				p.Go(func() {
+					n := running.Add(1)
+					for {
+						m := peak.Load()
+						if n <= m || peak.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(2 * time.Millisecond)
+					running.Add(-1)
+					completed.Add(1)
+				})
+			}
+
+			p.Collect()
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		p.Wait()
+		if completed.Load() != 40 {
+			t.Errorf("Jobs expected: %d, got: %d", 40, completed.Load())
+		}
+		if peak.Load() != 4 {
+			t.Errorf("Expected 4 jobs running at peak, got %d", peak.Load())
+		}
+	})
+
+	t.Run("elastic option is inherited by ContextPool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(3, pool.WithElastic(1, time.Millisecond)).WithErrors().WithContext(context.Background())
+		var completed atomic.Int64
+
+		for i := 0; i < 30; i++ {
+			p.Go(func(c context.Context) error {
+				completed.Add(1)
+				return nil
+			})
+			if i%10 == 0 {
+				time.Sleep(3 * time.Millisecond)
+			}
+		}
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if completed.Load() != 30 {
+			t.Errorf("Jobs expected: %d, got: %d", 30, completed.Load())
+		}
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("paces job starts", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4, pool.WithRateLimit(200, 1))
+		start := time.Now()
+
+		for i := 0; i < 6; i++ {
+			p.Go(func() {})
+		}
+		p.Wait()
+
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("Expected 6 jobs to take at least 20ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("ContextPool jobs stop waiting on cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := pool.New(2, pool.WithRateLimit(0.1, 1)).WithErrors().WithContext(ctx)
+
+		for i := 0; i < 3; i++ {
+			p.Go(func(ctx context.Context) error {
+				return ctx.Err()
+			})
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+
+		done := make(chan []error)
+		go func() { done <- p.Wait() }()
+
+		select {
+		case errs := <-done:
+			if len(errs) != 2 {
+				t.Errorf("Expected 2 errors from cancelled jobs, got %d", len(errs))
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected waiting jobs to stop waiting once the context is cancelled")
+		}
+	})
+
+	t.Run("ContextPool jobs keep waiting past a far deadline", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		p := pool.New(2, pool.WithRateLimit(50, 1)).WithErrors().WithContext(ctx)
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			p.Go(func(ctx context.Context) error {
+				return ctx.Err()
+			})
+		}
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+			t.Errorf("Expected 3 jobs to take at least 30ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("ContextPool jobs wait for the limiter until their deadline", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		p := pool.New(2, pool.WithRateLimit(1, 1)).WithErrors().WithContext(ctx)
+
+		for i := 0; i < 2; i++ {
+			p.Go(func(ctx context.Context) error {
+				return ctx.Err()
+			})
+		}
+
+		errs := p.Wait()
+		if len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+			t.Errorf("Expected 1 context.DeadlineExceeded error, got %v", errs)
+		}
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("Expected the second job to wait for the deadline, took %v", elapsed)
+		}
+	})
+
+	t.Run("ignores a rate of 0 or less", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithRateLimit(0, 1))
+
+		var n atomic.Int64
+		for i := 0; i < 5; i++ {
+			p.Go(func() { n.Add(1) })
+		}
+		p.Wait()
+
+		if n.Load() != 5 {
+			t.Errorf("Expected 5 jobs to run, got %d", n.Load())
+		}
+	})
 }
@@ -0,0 +1,111 @@
+package pool
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// task is a job waiting in the queue of a pool.
+type task struct {
+	fn func()
+	// id is the identifier of the task given to GoWithID(), reported by Stop() if the task is dropped.
+	id string
+	// key is the key of a task submitted to a keyed pool, reported by its Stop() if the task is dropped.
+	key any
+	// stateful, if not nil, is run instead of fn with a function returning the state of the worker that picks up the task.
+	stateful func(state func() any)
+	// run is the job that the worker runs, set by the pool when the task is submitted.
+	run      func(w *worker)
+	priority int
+	weight   int
+	// drop, if not nil, is called with the reason when the task is removed from the queue without being run.
+	drop func(error)
+	// hold, if not nil, is called with the mutex of the pool held once the task has been admitted, and reports whether
+	// the task is held back instead of being queued. A held task is queued later using unhold().
+	hold func() bool
+	// done, if not nil, is called once the job of the task has finished running, unless it is to be retried.
+	done func()
+
+	// attempts is the number of times the job of the task has been run, and err the error of the last attempt.
+	attempts int
+	err      error
+	// retrying is set by a job that failed and has to be run again once backoff has passed.
+	retrying bool
+	backoff  time.Duration
+	// ctx, if not nil, bounds the wait for the rate limiter of the pool before the task runs.
+	ctx context.Context
+
+	rank  int64
+	seq   uint64
+	index int
+}
+
+// queue is a priority queue of tasks. Tasks with a higher rank are popped first, and tasks of the same rank in FIFO order.
+//
+// Without aging, the rank of a task is its priority. With aging, the priority of a waiting task grows by one every aging period.
+// Since all waiting tasks age at the same rate, their order only depends on priority*aging - enqueue time, which is used as the rank.
+type queue struct {
+	tasks []*task
+
+	aging time.Duration
+	start time.Time
+	seq   uint64
+}
+
+func (q *queue) push(t *task) {
+	t.rank, t.seq = int64(t.priority), q.seq
+	if q.aging > 0 {
+		t.rank = int64(t.priority)*int64(q.aging) - int64(time.Since(q.start))
+	}
+
+	q.seq++
+	heap.Push(q, t)
+}
+
+func (q *queue) pop() *task {
+	return heap.Pop(q).(*task)
+}
+
+// remove removes the task from the queue, and reports whether it was still queued.
+func (q *queue) remove(t *task) bool {
+	if t.index < 0 || t.index >= len(q.tasks) || q.tasks[t.index] != t {
+		return false
+	}
+
+	heap.Remove(q, t.index)
+	return true
+}
+
+func (q *queue) Len() int {
+	return len(q.tasks)
+}
+
+func (q *queue) Less(i, j int) bool {
+	a, b := q.tasks[i], q.tasks[j]
+	if a.rank != b.rank {
+		return a.rank > b.rank
+	}
+	return a.seq < b.seq
+}
+
+func (q *queue) Swap(i, j int) {
+	q.tasks[i], q.tasks[j] = q.tasks[j], q.tasks[i]
+	q.tasks[i].index = i
+	q.tasks[j].index = j
+}
+
+func (q *queue) Push(x any) {
+	t := x.(*task)
+	t.index = len(q.tasks)
+	q.tasks = append(q.tasks, t)
+}
+
+func (q *queue) Pop() any {
+	n := len(q.tasks)
+	t := q.tasks[n-1]
+	q.tasks[n-1] = nil
+	q.tasks = q.tasks[:n-1]
+	t.index = -1
+	return t
+}
@@ -0,0 +1,122 @@
+package pool_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs queued jobs by priority", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(10))
+		started, release := make(chan struct{}), make(chan struct{})
+		p.Go(func() {
+			close(started)
+			<-release
+		})
+		<-started
+
+		var mu sync.Mutex
+		var order []string
+		record := func(s string) func() {
+			return func() {
+				mu.Lock()
+				order = append(order, s)
+				mu.Unlock()
+			}
+		}
+
+		p.GoPriority(0, record("low"))
+		p.GoPriority(5, record("high-1"))
+		p.Go(record("default"))
+		p.GoPriority(5, record("high-2"))
+		p.GoPriority(3, record("medium"))
+		p.GoPriority(-1, record("lowest"))
+
+		close(release)
+		p.Wait()
+
+		expected := []string{"high-1", "high-2", "medium", "low", "default", "lowest"}
+		if !slices.Equal(order, expected) {
+			t.Errorf("Expected order %v, got %v", expected, order)
+		}
+	})
+
+	t.Run("ages low priority jobs", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(10), pool.WithPriorityAging(time.Millisecond))
+		started, release := make(chan struct{}), make(chan struct{})
+		p.Go(func() {
+			close(started)
+			<-release
+		})
+		<-started
+
+		var mu sync.Mutex
+		var order []string
+		record := func(s string) func() {
+			return func() {
+				mu.Lock()
+				order = append(order, s)
+				mu.Unlock()
+			}
+		}
+
+		p.GoPriority(0, record("old"))
+		time.Sleep(20 * time.Millisecond)
+		p.GoPriority(5, record("new"))
+
+		close(release)
+		p.Wait()
+
+		expected := []string{"old", "new"}
+		if !slices.Equal(order, expected) {
+			t.Errorf("Expected order %v, got %v", expected, order)
+		}
+	})
+
+	t.Run("works with ContextPool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(10)).WithErrors().WithContext(context.Background())
+		started, release := make(chan struct{}), make(chan struct{})
+		p.Go(func(c context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		<-started
+
+		var mu sync.Mutex
+		var order []int
+		for i := 0; i < 5; i++ {
+			p.GoPriority(i, func(c context.Context) error {
+				mu.Lock()
+				order = append(order, i)
+				mu.Unlock()
+				return fmt.Errorf("err%d", i)
+			})
+		}
+
+		close(release)
+		errs := p.Wait()
+		if len(errs) != 5 {
+			t.Errorf("Expected 5 errors, got %d", len(errs))
+		}
+
+		expected := []int{4, 3, 2, 1, 0}
+		if !slices.Equal(order, expected) {
+			t.Errorf("Expected order %v, got %v", expected, order)
+		}
+	})
+}
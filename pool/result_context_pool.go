@@ -0,0 +1,59 @@
+package pool
+
+import "context"
+
+// ResultContextPool extends ContextPool to collect the values returned by jobs.
+//
+// A new result context pool must be created using WithResults(New()).WithErrors().WithContext(). Jobs can be submitted using Go() or TryGo().
+// The result context pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected results and errors.
+// Values returned by jobs that also return an error are discarded.
+type ResultContextPool[T any] struct {
+	contextPool *ContextPool
+
+	results *results[T]
+}
+
+// Go submits a job to the result context pool.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ResultContextPool[T]) Go(job func(context.Context) (T, error)) {
+	p.contextPool.Go(p.wrap(job))
+}
+
+// TryGo attempts to submit a job to the result context pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *ResultContextPool[T]) TryGo(job func(context.Context) (T, error)) bool {
+	return p.contextPool.TryGo(p.wrap(job))
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected results and errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the result context pool and stop the goroutine workers.
+func (p *ResultContextPool[T]) Collect() ([]T, []error) {
+	errs := p.contextPool.Collect()
+	return p.results.get(), errs
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and returns collected results and errors.
+//
+// After calling Wait(), the result context pool is considered closed; new jobs will be dropped.
+func (p *ResultContextPool[T]) Wait() ([]T, []error) {
+	errs := p.contextPool.Wait()
+	return p.results.get(), errs
+}
+
+func (p *ResultContextPool[T]) wrap(job func(context.Context) (T, error)) func(context.Context) error {
+	idx := p.results.reserve()
+	return func(ctx context.Context) error {
+		value, err := job(ctx)
+		if err != nil {
+			return err
+		}
+
+		p.results.add(idx, value)
+		return nil
+	}
+}
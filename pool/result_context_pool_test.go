@@ -0,0 +1,82 @@
+package pool_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestResultContextPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collects results and passes context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p := pool.WithResults[int](pool.New(4), pool.WithOrderedResults()).WithErrors().WithContext(ctx)
+		jobCount := 30
+		var ctxUsed atomic.Int64
+
+		for i := 0; i < jobCount; i++ {
+			p.Go(func(c context.Context) (int, error) {
+				if c != nil && c != context.Background() {
+					ctxUsed.Add(1)
+				}
+				return i * 2, nil
+			})
+		}
+
+		results, errs := p.Wait()
+		if errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if ctxUsed.Load() != int64(jobCount) {
+			t.Errorf("Expected all jobs to receive context, got %d/%d", ctxUsed.Load(), jobCount)
+		}
+		if len(results) != jobCount {
+			t.Fatalf("Expected %d results, got %d", jobCount, len(results))
+		}
+		for i, v := range results {
+			if v != i*2 {
+				t.Errorf("Expected result %d at index %d, got %d", i*2, i, v)
+			}
+		}
+	})
+
+	t.Run("cancels on error correctly", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(2)).WithErrors(pool.WithOnlyFirstErr()).WithContext(context.Background(), pool.WithCancelOnErr())
+
+		p.Go(func(c context.Context) (int, error) {
+			return 0, fmt.Errorf("intentional error")
+		})
+		for i := 0; i < 5; i++ {
+			p.Go(func(c context.Context) (int, error) {
+				select {
+				case <-c.Done():
+					return 0, c.Err()
+				case <-time.After(time.Second):
+					return i, nil
+				}
+			})
+		}
+
+		results, errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected only first error, got %d", len(errs))
+		}
+		if errs[0].Error() != "intentional error" {
+			t.Errorf("Expected 'intentional error', got: %v", errs[0])
+		}
+		if len(results) != 0 {
+			t.Errorf("Expected no results from cancelled jobs, got %v", results)
+		}
+	})
+}
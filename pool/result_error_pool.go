@@ -0,0 +1,69 @@
+package pool
+
+import "context"
+
+// ResultErrorPool extends ErrorPool to collect the values returned by jobs.
+//
+// A new result error pool must be created using WithResults(New()).WithErrors(). Jobs can be submitted using Go() or TryGo().
+// The result error pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected results and errors.
+// Values returned by jobs that also return an error are discarded.
+type ResultErrorPool[T any] struct {
+	errorPool *ErrorPool
+
+	results *results[T]
+}
+
+// Go submits a job to the result error pool.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ResultErrorPool[T]) Go(job func() (T, error)) {
+	p.errorPool.Go(p.wrap(job))
+}
+
+// TryGo attempts to submit a job to the result error pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *ResultErrorPool[T]) TryGo(job func() (T, error)) bool {
+	return p.errorPool.TryGo(p.wrap(job))
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected results and errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the result error pool and stop the goroutine workers.
+func (p *ResultErrorPool[T]) Collect() ([]T, []error) {
+	errs := p.errorPool.Collect()
+	return p.results.get(), errs
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and returns collected results and errors.
+//
+// After calling Wait(), the result error pool is considered closed; new jobs will be dropped.
+func (p *ResultErrorPool[T]) Wait() ([]T, []error) {
+	errs := p.errorPool.Wait()
+	return p.results.get(), errs
+}
+
+// WithContext converts the ResultErrorPool to a ResultContextPool
+//
+// ResultContextPool accepts jobs that expect a context.Context as a parameter and return a value of type T and can return errors.
+func (p *ResultErrorPool[T]) WithContext(ctx context.Context, opts ...OptionCtx) *ResultContextPool[T] {
+	return &ResultContextPool[T]{
+		contextPool: p.errorPool.WithContext(ctx, opts...),
+		results:     p.results,
+	}
+}
+
+func (p *ResultErrorPool[T]) wrap(job func() (T, error)) func() error {
+	idx := p.results.reserve()
+	return func() error {
+		value, err := job()
+		if err != nil {
+			return err
+		}
+
+		p.results.add(idx, value)
+		return nil
+	}
+}
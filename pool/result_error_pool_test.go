@@ -0,0 +1,95 @@
+package pool_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestResultErrorPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("collects results and errors", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(4), pool.WithOrderedResults()).WithErrors()
+		jobCount := 50
+
+		for i := 0; i < jobCount; i++ {
+			p.Go(func() (int, error) {
+				time.Sleep(time.Duration(jobCount-i) * 50 * time.Microsecond)
+				if i%5 == 0 {
+					return 0, fmt.Errorf("err%d", i)
+				}
+				return i, nil
+			})
+		}
+
+		results, errs := p.Wait()
+		if len(errs) != 10 {
+			t.Errorf("Expected 10 errors, got %d", len(errs))
+		}
+		if len(results) != 40 {
+			t.Fatalf("Expected 40 results, got %d", len(results))
+		}
+
+		prev := -1
+		for _, v := range results {
+			if v%5 == 0 {
+				t.Errorf("Expected results of errored jobs to be discarded, got %d", v)
+			}
+			if v <= prev {
+				t.Errorf("Expected results in submission order, got %d after %d", v, prev)
+			}
+			prev = v
+		}
+	})
+
+	t.Run("returns only first error", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(3)).WithErrors(pool.WithOnlyFirstErr())
+
+		for i := 0; i < 20; i++ {
+			p.Go(func() (int, error) {
+				if i%2 == 0 {
+					return 0, fmt.Errorf("err%d", i)
+				}
+				return i, nil
+			})
+		}
+
+		results, errs := p.Wait()
+		if len(errs) != 1 {
+			t.Errorf("Expected only one error, got %d", len(errs))
+		}
+		if len(results) != 10 {
+			t.Errorf("Expected 10 results, got %d", len(results))
+		}
+	})
+
+	t.Run("collects results correctly before Wait", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(2)).WithErrors()
+		p.Go(func() (int, error) { return 1, nil })
+		p.Go(func() (int, error) { return 0, fmt.Errorf("err") })
+
+		results, errs := p.Collect()
+		if len(results) != 1 || len(errs) != 1 {
+			t.Errorf("Expected 1 result and 1 error after Collect, got %d and %d", len(results), len(errs))
+		}
+
+		p.Go(func() (int, error) { return 2, nil })
+
+		results, errs = p.Wait()
+		if len(results) != 1 || results[0] != 2 {
+			t.Errorf("Expected [2] after Wait, got %v", results)
+		}
+		if errs != nil {
+			t.Errorf("Expected no errors after Wait, got %v", errs)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package pool
+
+import (
+	"slices"
+	"sync"
+)
+
+type OptionResult func(*resultOptions)
+
+type resultOptions struct {
+	ordered bool
+}
+
+// WithOrderedResults makes a Result Pool return results in the order the jobs were submitted.
+// By default, results are returned in the order the jobs finished.
+func WithOrderedResults() OptionResult {
+	return func(o *resultOptions) {
+		o.ordered = true
+	}
+}
+
+// ResultPool extends Pool to collect the values returned by jobs.
+//
+// A new result pool must be created using WithResults(New()). Jobs can be submitted using Go() or TryGo().
+// The result pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected results.
+type ResultPool[T any] struct {
+	pool *Pool
+
+	results *results[T]
+}
+
+// WithResults converts the Pool to a ResultPool.
+//
+// Go does not allow methods to introduce their own type parameters, so unlike WithErrors() this is a function rather than a method.
+// ResultPool accepts jobs that return a value of type T.
+func WithResults[T any](p *Pool, opts ...OptionResult) *ResultPool[T] {
+	var o resultOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &ResultPool[T]{
+		pool:    p,
+		results: &results[T]{ordered: o.ordered},
+	}
+}
+
+// Go submits a job to the result pool.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+func (p *ResultPool[T]) Go(job func() T) {
+	idx := p.results.reserve()
+	p.pool.Go(func() {
+		p.results.add(idx, job())
+	})
+}
+
+// TryGo attempts to submit a job to the result pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *ResultPool[T]) TryGo(job func() T) bool {
+	idx := p.results.reserve()
+	return p.pool.TryGo(func() {
+		p.results.add(idx, job())
+	})
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected results.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the result pool and stop the goroutine workers.
+func (p *ResultPool[T]) Collect() []T {
+	p.pool.Collect()
+	return p.results.get()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and returns collected results.
+//
+// After calling Wait(), the result pool is considered closed; new jobs will be dropped.
+func (p *ResultPool[T]) Wait() []T {
+	p.pool.Wait()
+	return p.results.get()
+}
+
+// WithErrors converts the ResultPool to a ResultErrorPool
+//
+// ResultErrorPool accepts jobs that return a value of type T and can return errors.
+func (p *ResultPool[T]) WithErrors(opts ...Option) *ResultErrorPool[T] {
+	return &ResultErrorPool[T]{
+		errorPool: p.pool.WithErrors(opts...),
+		results:   p.results,
+	}
+}
+
+type indexed[T any] struct {
+	idx   int
+	value T
+}
+
+// results stores values returned by jobs along with the order in which the jobs were submitted.
+type results[T any] struct {
+	ordered bool
+
+	mu     sync.Mutex
+	next   int
+	values []indexed[T]
+}
+
+func (r *results[T]) reserve() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := r.next
+	r.next++
+	return idx
+}
+
+func (r *results[T]) add(idx int, value T) {
+	r.mu.Lock()
+	r.values = append(r.values, indexed[T]{idx: idx, value: value})
+	r.mu.Unlock()
+}
+
+func (r *results[T]) get() []T {
+	r.mu.Lock()
+	values := r.values
+	r.values = nil
+	r.mu.Unlock()
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if r.ordered {
+		slices.SortFunc(values, func(a, b indexed[T]) int {
+			return a.idx - b.idx
+		})
+	}
+
+	out := make([]T, len(values))
+	for i, v := range values {
+		out[i] = v.value
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package pool_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestResultPool(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range basicCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := pool.WithResults[int](pool.New(tc.workers))
+
+			for i := 0; i < tc.jobCount; i++ {
+				p.Go(func() int {
+					time.Sleep(2 * time.Millisecond)
+					return i
+				})
+			}
+
+			results := p.Wait()
+			if len(results) != tc.jobCount {
+				t.Fatalf("Results expected: %d, got: %d", tc.jobCount, len(results))
+			}
+
+			sort.Ints(results)
+			for i, v := range results {
+				if v != i {
+					t.Errorf("Expected result %d, got %d", i, v)
+				}
+			}
+		})
+	}
+
+	t.Run("returns results in submission order", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(5), pool.WithOrderedResults())
+		jobCount := 30
+
+		for i := 0; i < jobCount; i++ {
+			p.Go(func() int {
+				time.Sleep(time.Duration(jobCount-i) * 100 * time.Microsecond)
+				return i
+			})
+		}
+
+		results := p.Wait()
+		if len(results) != jobCount {
+			t.Fatalf("Results expected: %d, got: %d", jobCount, len(results))
+		}
+		for i, v := range results {
+			if v != i {
+				t.Errorf("Expected result %d at index %d, got %d", i, i, v)
+			}
+		}
+	})
+
+	t.Run("collects results correctly before Wait", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[string](pool.New(3), pool.WithOrderedResults())
+		for _, s := range []string{"a", "b", "c"} {
+			p.Go(func() string { return s })
+		}
+
+		results := p.Collect()
+		if len(results) != 3 || results[0] != "a" || results[1] != "b" || results[2] != "c" {
+			t.Errorf("Expected [a b c] after Collect, got %v", results)
+		}
+
+		for _, s := range []string{"d", "e"} {
+			p.Go(func() string { return s })
+		}
+
+		results = p.Wait()
+		if len(results) != 2 || results[0] != "d" || results[1] != "e" {
+			t.Errorf("Expected [d e] after Wait, got %v", results)
+		}
+	})
+
+	t.Run("handles TryGo correctly", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(1))
+		ok := p.TryGo(func() int { return 1 })
+		if !ok {
+			t.Fatalf("Should not error on .TryGo() if the Pool is not closed yet")
+		}
+
+		results := p.Wait()
+		if len(results) != 1 {
+			t.Errorf("Expected 1 result, got %d", len(results))
+		}
+
+		ok = p.TryGo(func() int { return 2 })
+		if ok {
+			t.Fatalf("Expected error on .TryGo() because Pool is closed")
+		}
+	})
+
+	t.Run("returns nil if no jobs", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithResults[int](pool.New(2))
+		if results := p.Wait(); results != nil {
+			t.Errorf("Expected nil results, got %v", results)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// WithRetry makes an Error Pool re-run failed jobs according to the given retry policy.
+//
+// A failed job is submitted to the pool again once its backoff has passed, so it does not hold a worker, room in the queue
+// or weight while it waits. A Context Pool created from the Error Pool retries its jobs too, and stops waiting for the next attempt
+// once its context is cancelled.
+func WithRetry(policy RetryPolicy) Option {
+	return func(p *ErrorPool) {
+		p.retryPolicy = &policy
+	}
+}
+
+// RetryPolicy describes how failed jobs are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a job is run, including the first attempt.
+	// Values of 1 or less mean that jobs are not retried.
+	MaxAttempts int
+	// Backoff returns how long to wait before the next attempt. If nil, jobs are retried immediately.
+	Backoff Backoff
+	// Retryable reports whether a job that failed with the given error should be retried.
+	// If nil, all errors are retried, except for panics.
+	Retryable func(error) bool
+}
+
+// Backoff returns how long to wait after the given failed attempt, counting from 1.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff waits the same duration after every failed attempt.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff waits base after the first failed attempt and doubles the wait after every following one, up to limit.
+func ExponentialBackoff(base, limit time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < limit; i++ {
+			d *= 2
+		}
+		return min(d, limit)
+	}
+}
+
+// JitteredBackoff waits a random duration between zero and the duration returned by the given backoff,
+// which spreads out retries of jobs that failed at the same time.
+func JitteredBackoff(b Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		if d <= 0 {
+			return 0
+		}
+		return rand.N(d + 1)
+	}
+}
+
+// errRetry is returned by the attempt of a job that failed and is to be run again, so that its error is not collected.
+var errRetry = errors.New("pool: retrying")
+
+// RetryError is returned for jobs of a pool configured with WithRetry() that were run more than once and failed on their final attempt.
+type RetryError struct {
+	// Err is the error returned by the final attempt.
+	Err error
+	// Attempts is the number of times the job was run.
+	Attempts int
+}
+
+// Error returns the error of the final attempt along with the number of attempts.
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("pool: job failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the final attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// attempt runs the job once as an attempt of the task.
+//
+// If the job fails with a retryable error and has attempts left, the task is marked to be queued again once the backoff has passed,
+// and errRetry is returned. If the context of the task was done while it waited for the attempt, the job is not run again,
+// and the error of the previous attempt is returned.
+func (r *RetryPolicy) attempt(t *task, job func() error) error {
+	if t.attempts > 0 && t.ctx != nil && t.ctx.Err() != nil {
+		return r.failed(t.err, t.attempts)
+	}
+
+	t.attempts++
+	err := catchErr(job)
+	if err == nil {
+		return nil
+	}
+	if t.attempts >= r.MaxAttempts || !r.retryable(err) {
+		return r.failed(err, t.attempts)
+	}
+
+	t.err = err
+	t.retrying = true
+	t.backoff = 0
+	if r.Backoff != nil {
+		t.backoff = r.Backoff(t.attempts)
+	}
+	return errRetry
+}
+
+// failed returns the final error of a job, wrapped in a *RetryError if the job was run more than once.
+func (r *RetryPolicy) failed(err error, attempts int) error {
+	if attempts > 1 {
+		return &RetryError{Err: err, Attempts: attempts}
+	}
+	return err
+}
+
+func (r *RetryPolicy) retryable(err error) bool {
+	if r.Retryable != nil {
+		return r.Retryable(err)
+	}
+
+	var pe *PanicError
+	return !errors.As(err, &pe)
+}
@@ -0,0 +1,258 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries failed jobs until they succeed", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(3).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 5,
+			Backoff:     pool.ConstantBackoff(time.Millisecond),
+		}))
+
+		var attempts atomic.Int64
+		for i := 0; i < 10; i++ {
+			var n atomic.Int64
+			p.Go(func() error {
+				attempts.Add(1)
+				if n.Add(1) < 3 {
+					return fmt.Errorf("flaky")
+				}
+				return nil
+			})
+		}
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if attempts.Load() != 30 {
+			t.Errorf("Expected 30 attempts, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("reports final error with attempt count", func(t *testing.T) {
+		t.Parallel()
+
+		sentinel := errors.New("sentinel")
+		p := pool.New(1).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     pool.JitteredBackoff(pool.ExponentialBackoff(time.Millisecond, 4*time.Millisecond)),
+		}))
+
+		p.Go(func() error { return sentinel })
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %d", len(errs))
+		}
+
+		var re *pool.RetryError
+		if !errors.As(errs[0], &re) {
+			t.Fatalf("Expected *pool.RetryError, got %v", errs[0])
+		}
+		if re.Attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", re.Attempts)
+		}
+		if !errors.Is(errs[0], sentinel) {
+			t.Errorf("Expected error to unwrap to the job error, got %v", errs[0])
+		}
+	})
+
+	t.Run("does not retry errors that are not retryable", func(t *testing.T) {
+		t.Parallel()
+
+		permanent := errors.New("permanent")
+		p := pool.New(2).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 5,
+			Retryable:   func(err error) bool { return !errors.Is(err, permanent) },
+		}))
+
+		var permanentAttempts, panicAttempts atomic.Int64
+		p.Go(func() error {
+			permanentAttempts.Add(1)
+			return permanent
+		})
+		p.Go(func() error {
+			panicAttempts.Add(1)
+			panic("intentional panic")
+		})
+
+		errs := p.Wait()
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %d", len(errs))
+		}
+		if permanentAttempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt for a non-retryable error, got %d", permanentAttempts.Load())
+		}
+		if panicAttempts.Load() != 5 {
+			t.Errorf("Expected 5 attempts for a panic with a custom Retryable, got %d", panicAttempts.Load())
+		}
+	})
+
+	t.Run("stops waiting for the next attempt on cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		p := pool.New(1).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 10,
+			Backoff:     pool.ConstantBackoff(time.Hour),
+		})).WithContext(ctx)
+
+		var attempts atomic.Int64
+		p.Go(func(c context.Context) error {
+			attempts.Add(1)
+			return fmt.Errorf("flaky")
+		})
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %d", len(errs))
+		}
+		if attempts.Load() != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts.Load())
+		}
+
+		var re *pool.RetryError
+		if errors.As(errs[0], &re) {
+			t.Errorf("Expected the error of the only attempt, got %v", errs[0])
+		}
+	})
+
+	t.Run("does not wrap errors of jobs that ran once", func(t *testing.T) {
+		t.Parallel()
+
+		sentinel := errors.New("sentinel")
+		p := pool.New(1).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 5,
+			Retryable:   func(err error) bool { return !errors.Is(err, sentinel) },
+		}))
+
+		p.Go(func() error { return sentinel })
+
+		errs := p.Wait()
+		if len(errs) != 1 {
+			t.Fatalf("Expected 1 error, got %d", len(errs))
+		}
+		if errs[0] != sentinel {
+			t.Errorf("Expected %v, got %v", sentinel, errs[0])
+		}
+	})
+
+	t.Run("does not hold a worker during the backoff", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 2,
+			Backoff:     pool.ConstantBackoff(50 * time.Millisecond),
+		}))
+
+		var n atomic.Int64
+		p.Go(func() error {
+			if n.Add(1) == 1 {
+				return fmt.Errorf("flaky")
+			}
+			return nil
+		})
+
+		done := make(chan struct{})
+		time.Sleep(5 * time.Millisecond)
+		p.Go(func() error {
+			close(done)
+			return nil
+		})
+
+		select {
+		case <-done:
+		case <-time.After(40 * time.Millisecond):
+			t.Errorf("Expected the second job to run during the backoff of the first")
+		}
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if n.Load() != 2 {
+			t.Errorf("Expected 2 attempts, got %d", n.Load())
+		}
+	})
+
+	t.Run("waits for retries of an elastic pool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithElastic(0, time.Millisecond)).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     pool.ConstantBackoff(50 * time.Millisecond),
+		}))
+
+		var n atomic.Int64
+		p.Go(func() error {
+			if n.Add(1) < 3 {
+				return fmt.Errorf("flaky")
+			}
+			return nil
+		})
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if n.Load() != 3 {
+			t.Errorf("Expected 3 attempts before Wait returned, got %d", n.Load())
+		}
+	})
+
+	t.Run("does not cancel ContextPool before retries run out", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1).WithErrors(pool.WithRetry(pool.RetryPolicy{
+			MaxAttempts: 3,
+		})).WithContext(context.Background(), pool.WithCancelOnErr())
+
+		var n atomic.Int64
+		p.Go(func(c context.Context) error {
+			if err := c.Err(); err != nil {
+				return err
+			}
+			if n.Add(1) < 3 {
+				return fmt.Errorf("flaky")
+			}
+			return nil
+		})
+
+		if errs := p.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	exp := pool.ExponentialBackoff(time.Millisecond, 10*time.Millisecond)
+	expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond}
+	for i, d := range expected {
+		if got := exp(i + 1); got != d {
+			t.Errorf("Expected exponential backoff %v for attempt %d, got %v", d, i+1, got)
+		}
+	}
+
+	jittered := pool.JitteredBackoff(pool.ConstantBackoff(5 * time.Millisecond))
+	for i := 1; i <= 100; i++ {
+		if got := jittered(i); got < 0 || got > 5*time.Millisecond {
+			t.Errorf("Expected jittered backoff within [0, 5ms], got %v", got)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package pool
+
+import (
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the buckets of the latency histogram.
+var latencyBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// Stats is a snapshot of the runtime statistics of a pool, returned by Stats().
+type Stats struct {
+	// Workers is the number of workers the pool is configured with, as returned by Workers().
+	Workers int
+	// LiveWorkers is the number of worker goroutines currently alive.
+	LiveWorkers int
+	// IdleWorkers is the number of live workers currently waiting for a job.
+	IdleWorkers int
+
+	// Submitted is the number of jobs accepted by the pool.
+	Submitted uint64
+	// Queued is the number of accepted jobs waiting for a worker, including jobs of a KeyedPool waiting for their key.
+	Queued int
+	// Running is the number of jobs currently being run by workers.
+	Running int
+	// Weight is the total weight of the queued and running jobs, or 0 if the pool has no weight capacity (see WithWeightCapacity()).
+	Weight int
+	// Completed is the number of jobs that finished running, including failed ones. Every attempt of a retried job is counted.
+	Completed uint64
+	// Failed is the number of jobs that returned an error or panicked.
+	Failed uint64
+	// Panicked is the number of jobs that panicked.
+	Panicked uint64
+	// Dropped is the number of jobs submitted after Wait() has been called, or discarded from the queue by Stop().
+	Dropped uint64
+	// Rejected is the number of jobs not accepted because the queue was full, as reported by TryGo() and GoContext().
+	Rejected uint64
+
+	// Latency is the histogram of the time it took the completed jobs to run.
+	Latency Histogram
+}
+
+// Histogram counts durations in buckets.
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets, in increasing order.
+	Bounds []time.Duration
+	// Counts holds the number of durations in each bucket. It has one more element than Bounds,
+	// the last one counting the durations above the largest bound.
+	Counts []uint64
+	// Sum is the total of all counted durations.
+	Sum time.Duration
+}
+
+// Count returns the total number of durations in the histogram.
+func (h Histogram) Count() uint64 {
+	var n uint64
+	for _, c := range h.Counts {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the average of the durations in the histogram, or 0 if it is empty.
+func (h Histogram) Mean() time.Duration {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(n)
+}
+
+// Stats returns a snapshot of the runtime statistics of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	s := Stats{
+		Workers:     p.workers,
+		LiveWorkers: p.live,
+		IdleWorkers: len(p.idle),
+		Queued:      p.queued(),
+		Running:     p.pending - p.queued(),
+		Weight:      p.weight,
+	}
+	p.mu.Unlock()
+
+	s.Submitted = p.stats.submitted.Load()
+	s.Completed = p.stats.completed.Load()
+	s.Failed = p.stats.failed.Load()
+	s.Panicked = p.stats.panicked.Load()
+	s.Dropped = p.stats.dropped.Load()
+	s.Rejected = p.stats.rejected.Load()
+	s.Latency = p.stats.histogram()
+
+	return s
+}
+
+// Stats returns a snapshot of the runtime statistics of the error pool.
+func (p *ErrorPool) Stats() Stats {
+	return p.pool.Stats()
+}
+
+// Stats returns a snapshot of the runtime statistics of the context pool.
+func (p *ContextPool) Stats() Stats {
+	return p.errorPool.Stats()
+}
+
+// stats holds the counters of a pool, which are updated without holding the pool's mutex.
+type stats struct {
+	submitted atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	panicked  atomic.Uint64
+	dropped   atomic.Uint64
+	rejected  atomic.Uint64
+
+	latency    [len(latencyBounds) + 1]atomic.Uint64
+	latencySum atomic.Int64
+}
+
+func (s *stats) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+
+	s.latency[i].Add(1)
+	s.latencySum.Add(int64(d))
+	s.completed.Add(1)
+}
+
+func (s *stats) fail(err error) {
+	if err == nil {
+		return
+	}
+
+	s.failed.Add(1)
+
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		s.panicked.Add(1)
+	}
+}
+
+func (s *stats) histogram() Histogram {
+	h := Histogram{
+		Bounds: slices.Clone(latencyBounds[:]),
+		Counts: make([]uint64, len(s.latency)),
+		Sum:    time.Duration(s.latencySum.Load()),
+	}
+	for i := range s.latency {
+		h.Counts[i] = s.latency[i].Load()
+	}
+	return h
+}
@@ -0,0 +1,146 @@
+package pool_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts jobs of a Pool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(1))
+		release := make(chan struct{})
+
+		for i := 0; i < 3; i++ {
+			p.Go(func() { <-release })
+		}
+
+		s := p.Stats()
+		if s.Workers != 2 || s.LiveWorkers != 2 {
+			t.Errorf("Expected 2 workers and 2 live workers, got %d and %d", s.Workers, s.LiveWorkers)
+		}
+		if s.Submitted != 3 {
+			t.Errorf("Expected 3 submitted jobs, got %d", s.Submitted)
+		}
+		if s.Running+s.Queued != 3 {
+			t.Errorf("Expected 3 running or queued jobs, got %d running and %d queued", s.Running, s.Queued)
+		}
+
+		if p.TryGo(func() {}) {
+			t.Fatalf("Expected .TryGo() to fail on a full queue")
+		}
+
+		close(release)
+		p.Collect()
+		p.Go(func() { time.Sleep(2 * time.Millisecond) })
+		p.Wait()
+		p.Go(func() {})
+
+		s = p.Stats()
+		if s.Submitted != 4 {
+			t.Errorf("Expected 4 submitted jobs, got %d", s.Submitted)
+		}
+		if s.Completed != 4 {
+			t.Errorf("Expected 4 completed jobs, got %d", s.Completed)
+		}
+		if s.Running != 0 || s.Queued != 0 {
+			t.Errorf("Expected no running or queued jobs, got %d and %d", s.Running, s.Queued)
+		}
+		if s.Rejected != 1 {
+			t.Errorf("Expected 1 rejected job, got %d", s.Rejected)
+		}
+		if s.Dropped != 1 {
+			t.Errorf("Expected 1 dropped job, got %d", s.Dropped)
+		}
+		if s.LiveWorkers != 0 {
+			t.Errorf("Expected no live workers after Wait, got %d", s.LiveWorkers)
+		}
+		if s.Latency.Count() != 4 {
+			t.Errorf("Expected 4 latency observations, got %d", s.Latency.Count())
+		}
+		if len(s.Latency.Counts) != len(s.Latency.Bounds)+1 {
+			t.Errorf("Expected one more bucket than bounds, got %d buckets and %d bounds", len(s.Latency.Counts), len(s.Latency.Bounds))
+		}
+		if s.Latency.Mean() <= 0 {
+			t.Errorf("Expected positive mean latency, got %v", s.Latency.Mean())
+		}
+	})
+
+	t.Run("counts failures and panics of an ErrorPool", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(3).WithErrors()
+		for i := 0; i < 10; i++ {
+			p.Go(func() error {
+				switch {
+				case i == 0:
+					panic("intentional panic")
+				case i%3 == 0:
+					return fmt.Errorf("err%d", i)
+				}
+				return nil
+			})
+		}
+		p.Wait()
+
+		s := p.Stats()
+		if s.Completed != 10 {
+			t.Errorf("Expected 10 completed jobs, got %d", s.Completed)
+		}
+		if s.Failed != 4 {
+			t.Errorf("Expected 4 failed jobs, got %d", s.Failed)
+		}
+		if s.Panicked != 1 {
+			t.Errorf("Expected 1 panicked job, got %d", s.Panicked)
+		}
+	})
+
+	t.Run("counts failures of a ContextPool once", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1).WithErrors(pool.WithOnlyFirstErr()).WithContext(context.Background(), pool.WithCancelOnErr())
+		p.Go(func(c context.Context) error { return fmt.Errorf("intentional error") })
+		p.Wait()
+
+		if s := p.Stats(); s.Failed != 1 {
+			t.Errorf("Expected 1 failed job, got %d", s.Failed)
+		}
+	})
+
+	t.Run("reports idle workers of an elastic pool exiting", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4, pool.WithElastic(1, 2*time.Millisecond))
+		if s := p.Stats(); s.LiveWorkers != 1 {
+			t.Errorf("Expected 1 live worker before any jobs, got %d", s.LiveWorkers)
+		}
+
+		release := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			p.Go(func() { <-release })
+		}
+		if s := p.Stats(); s.LiveWorkers != 4 {
+			t.Errorf("Expected 4 live workers while busy, got %d", s.LiveWorkers)
+		}
+
+		close(release)
+		p.Collect()
+
+		deadline := time.Now().Add(time.Second)
+		for p.Stats().LiveWorkers != 1 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if s := p.Stats(); s.LiveWorkers != 1 {
+			t.Errorf("Expected idle workers to exit down to 1, got %d", s.LiveWorkers)
+		}
+
+		p.Wait()
+	})
+}
@@ -0,0 +1,222 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops queued jobs and waits for running ones", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(10))
+		started := make(chan struct{}, 2)
+		var completed atomic.Int64
+
+		for i := 0; i < 2; i++ {
+			p.Go(func() {
+				started <- struct{}{}
+				time.Sleep(5 * time.Millisecond)
+				completed.Add(1)
+			})
+		}
+		<-started
+		<-started
+		for i := 0; i < 10; i++ {
+			p.Go(func() { completed.Add(1) })
+		}
+
+		dropped, err := p.Stop(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(dropped) != 10 {
+			t.Errorf("Expected 10 dropped jobs, got %d", len(dropped))
+		}
+		if completed.Load() != 2 {
+			t.Errorf("Expected 2 completed jobs, got %d", completed.Load())
+		}
+		if s := p.Stats(); s.Dropped != 10 || s.Queued != 0 {
+			t.Errorf("Expected 10 dropped and 0 queued jobs, got %d and %d", s.Dropped, s.Queued)
+		}
+
+		if p.TryGo(func() {}) {
+			t.Error("Expected .TryGo() to return false after Stop")
+		}
+	})
+
+	t.Run("returns when ctx expires", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		p.Go(func() {
+			close(started)
+			<-release
+		})
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		_, err := p.Stop(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+
+		close(release)
+		p.Wait()
+	})
+
+	t.Run("unblocks waiting submitters", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1)
+		release := make(chan struct{})
+		p.Go(func() { <-release })
+
+		submitted := make(chan struct{})
+		go func() {
+			p.Go(func() {})
+			close(submitted)
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		if _, err := p.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+
+		select {
+		case <-submitted:
+		case <-time.After(time.Second):
+			t.Error("Expected the blocked .Go() to return after Stop")
+		}
+
+		close(release)
+		p.Wait()
+	})
+
+	t.Run("cancels running ContextPool jobs", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithQueueSize(5)).WithErrors().WithContext(context.Background())
+		started := make(chan struct{}, 2)
+
+		for i := 0; i < 7; i++ {
+			p.Go(func(ctx context.Context) error {
+				started <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			})
+		}
+		<-started
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		dropped, err := p.Stop(ctx)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if len(dropped) != 5 {
+			t.Errorf("Expected 5 dropped jobs, got %d", len(dropped))
+		}
+
+		errs := p.Wait()
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %d", len(errs))
+		}
+		for _, err := range errs {
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+		}
+	})
+
+	t.Run("returns the identifiers of dropped jobs", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(1, pool.WithQueueSize(5)).WithErrors()
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		p.GoWithID("running", func() error {
+			close(started)
+			<-release
+			return nil
+		})
+		<-started
+		p.GoWithID("a", func() error { return nil })
+		p.Go(func() error { return nil })
+		p.GoWithID("b", func() error { return nil })
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			close(release)
+		}()
+
+		dropped, err := p.Stop(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		slices.Sort(dropped)
+		if !slices.Equal(dropped, []string{"", "a", "b"}) {
+			t.Errorf("Expected dropped jobs [ a b], got %q", dropped)
+		}
+	})
+
+	t.Run("drops jobs waiting for their key", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithKeys[string](pool.New(2, pool.WithQueueSize(10)))
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var completed atomic.Int64
+
+		p.Go("a", func() {
+			close(started)
+			<-release
+			completed.Add(1)
+		})
+		<-started
+		for i := 0; i < 5; i++ {
+			p.Go("a", func() { completed.Add(1) })
+		}
+		p.Go("b", func() { completed.Add(1) })
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			close(release)
+		}()
+
+		dropped, err := p.Stop(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		p.Wait()
+
+		// the job of key b may have started before Stop
+		if n := completed.Load(); n != 1 && n != 2 {
+			t.Errorf("Expected 1 or 2 completed jobs, got %d", n)
+		}
+		if len(dropped)+int(completed.Load()) != 7 {
+			t.Errorf("Expected every job to be either completed or dropped, got %d dropped and %d completed", len(dropped), completed.Load())
+		}
+		if n := len(slices.DeleteFunc(dropped, func(key string) bool { return key != "a" })); n != 5 {
+			t.Errorf("Expected 5 dropped jobs of key a, got %d", n)
+		}
+	})
+}
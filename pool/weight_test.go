@@ -0,0 +1,138 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+func TestWeight(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keeps in-flight weight under capacity", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(8, pool.WithWeightCapacity(10))
+		var weight, peak atomic.Int64
+
+		for i := 0; i < 40; i++ {
+			w := 1 + i%5
+			p.GoWeighted(w, func() {
+				cur := weight.Add(int64(w))
+				for {
+					old := peak.Load()
+					if cur <= old || peak.CompareAndSwap(old, cur) {
+						break
+					}
+				}
+				time.Sleep(200 * time.Microsecond)
+				weight.Add(-int64(w))
+			})
+		}
+		p.Wait()
+
+		if peak.Load() > 10 {
+			t.Errorf("Expected in-flight weight of at most 10, got %d", peak.Load())
+		}
+		if s := p.Stats(); s.Weight != 0 {
+			t.Errorf("Expected weight of 0 after Wait, got %d", s.Weight)
+		}
+	})
+
+	t.Run("TryGoWeighted fails fast over capacity", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4, pool.WithWeightCapacity(5))
+		release := make(chan struct{})
+
+		if !p.TryGoWeighted(4, func() { <-release }) {
+			t.Fatal("Expected job of weight 4 to be accepted")
+		}
+		if p.TryGoWeighted(2, func() {}) {
+			t.Error("Expected job of weight 2 to be rejected")
+		}
+		if !p.TryGoWeighted(1, func() { <-release }) {
+			t.Error("Expected job of weight 1 to be accepted")
+		}
+		if s := p.Stats(); s.Weight != 5 || s.Rejected != 1 {
+			t.Errorf("Expected weight 5 and 1 rejected job, got %d and %d", s.Weight, s.Rejected)
+		}
+
+		close(release)
+		p.Collect()
+
+		if !p.TryGoWeighted(2, func() {}) {
+			t.Error("Expected job of weight 2 to be accepted once weight is released")
+		}
+		p.Wait()
+	})
+
+	t.Run("runs jobs heavier than capacity alone", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(4, pool.WithWeightCapacity(3))
+		var running, peak atomic.Int64
+
+		for i := 0; i < 10; i++ {
+			w := 1
+			if i%3 == 0 {
+				w = 100
+			}
+			p.GoWeighted(w, func() {
+				cur := running.Add(1)
+				if w == 100 && cur != 1 {
+					t.Errorf("Expected heavy job to run alone, got %d running", cur)
+				}
+				for {
+					old := peak.Load()
+					if cur <= old || peak.CompareAndSwap(old, cur) {
+						break
+					}
+				}
+				time.Sleep(100 * time.Microsecond)
+				running.Add(-1)
+			})
+		}
+		p.Wait()
+
+		if peak.Load() > 3 {
+			t.Errorf("Expected at most 3 jobs running, got %d", peak.Load())
+		}
+	})
+
+	t.Run("ContextPool GoWeighted blocks until weight is released", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.New(2, pool.WithWeightCapacity(2)).WithErrors().WithContext(context.Background())
+		release := make(chan struct{})
+		errTest := errors.New("test")
+
+		p.GoWeighted(2, func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		submitted := make(chan struct{})
+		go func() {
+			p.GoWeighted(1, func(ctx context.Context) error { return errTest })
+			close(submitted)
+		}()
+
+		select {
+		case <-submitted:
+			t.Fatal("Expected GoWeighted to block while capacity is used up")
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		close(release)
+		<-submitted
+
+		if err := p.WaitErr(); !errors.Is(err, errTest) {
+			t.Errorf("Expected error %v, got %v", errTest, err)
+		}
+	})
+}
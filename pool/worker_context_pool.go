@@ -0,0 +1,59 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WorkerContextPool extends ContextPool to give each worker its own state of type S, which is passed to every job the worker runs.
+//
+// A new worker context pool must be created using WithWorkerState(New()).WithErrors().WithContext(). Jobs can be submitted using Go() or TryGo().
+// The worker context pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected errors.
+type WorkerContextPool[S any] struct {
+	contextPool *ContextPool
+}
+
+// Go submits a job to the worker context pool, which receives the pool context and the state of the worker that runs it.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError, which also cancels the context when WithCancelOnErr() is set.
+func (p *WorkerContextPool[S]) Go(job func(context.Context, S) error) {
+	_ = p.contextPool.submit(context.Background(), p.task(job), true)
+}
+
+// TryGo attempts to submit a job to the worker context pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *WorkerContextPool[S]) TryGo(job func(context.Context, S) error) bool {
+	return p.contextPool.submit(context.Background(), p.task(job), false) == nil
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the worker context pool and stop the goroutine workers.
+func (p *WorkerContextPool[S]) Collect() []error {
+	return p.contextPool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and clean up their states, and returns collected errors.
+//
+// After calling Wait(), the worker context pool is considered closed; new jobs will be dropped.
+func (p *WorkerContextPool[S]) Wait() []error {
+	return p.contextPool.Wait()
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+func (p *WorkerContextPool[S]) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+func (p *WorkerContextPool[S]) task(job func(context.Context, S) error) *task {
+	t := &task{weight: 1}
+	t.stateful = func(state func() any) {
+		p.contextPool.wrap(t, func(ctx context.Context) error { return job(ctx, stateOf[S](state())) }, p.contextPool.jobTimeout, time.Time{})()
+	}
+	return t
+}
@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"context"
+	"errors"
+)
+
+// WorkerErrorPool extends ErrorPool to give each worker its own state of type S, which is passed to every job the worker runs.
+//
+// A new worker error pool must be created using WithWorkerState(New()).WithErrors(). Jobs can be submitted using Go() or TryGo().
+// The worker error pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and returns collected errors.
+type WorkerErrorPool[S any] struct {
+	errorPool *ErrorPool
+}
+
+// Go submits a job to the worker error pool, which receives the state of the worker that runs it.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the panic is recovered and collected as a *PanicError.
+func (p *WorkerErrorPool[S]) Go(job func(S) error) {
+	_ = p.errorPool.pool.submit(context.Background(), p.task(job), true)
+}
+
+// TryGo attempts to submit a job to the worker error pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *WorkerErrorPool[S]) TryGo(job func(S) error) bool {
+	return p.errorPool.pool.submit(context.Background(), p.task(job), false) == nil
+}
+
+// Collect blocks until all submitted jobs are finished and returns collected errors.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the worker error pool and stop the goroutine workers.
+func (p *WorkerErrorPool[S]) Collect() []error {
+	return p.errorPool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and clean up their states, and returns collected errors.
+//
+// After calling Wait(), the worker error pool is considered closed; new jobs will be dropped.
+func (p *WorkerErrorPool[S]) Wait() []error {
+	return p.errorPool.Wait()
+}
+
+// WaitErr closes the job queue and blocks until all workers finish the jobs and returns collected errors joined using errors.Join(), or nil if there are none.
+func (p *WorkerErrorPool[S]) WaitErr() error {
+	return errors.Join(p.Wait()...)
+}
+
+// WithContext converts the WorkerErrorPool to a WorkerContextPool
+//
+// WorkerContextPool accepts jobs that expect a ctx.context and the state of their worker as parameters and can return errors.
+func (p *WorkerErrorPool[S]) WithContext(ctx context.Context, opts ...OptionCtx) *WorkerContextPool[S] {
+	return &WorkerContextPool[S]{
+		contextPool: p.errorPool.WithContext(ctx, opts...),
+	}
+}
+
+func (p *WorkerErrorPool[S]) task(job func(S) error) *task {
+	t := &task{weight: 1}
+	t.stateful = func(state func() any) {
+		p.errorPool.wrap(t, func() error { return job(stateOf[S](state())) })()
+	}
+	return t
+}
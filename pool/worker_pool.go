@@ -0,0 +1,78 @@
+package pool
+
+import "context"
+
+// WorkerPool extends Pool to give each worker its own state of type S, such as a connection or a buffer,
+// which is passed to every job the worker runs.
+//
+// The state of a worker is created by the init function when the worker runs its first job, and is never shared with other workers.
+// It is cleaned up when the worker exits, which happens in Wait(), or earlier when the pool is shrunk or elastic.
+//
+// A new worker pool must be created using WithWorkerState(New()). Jobs can be submitted using Go() or TryGo().
+// The worker pool can be gracefully shut down using Wait(), which blocks until all submitted jobs are complete and the states are cleaned up.
+type WorkerPool[S any] struct {
+	pool *Pool
+}
+
+// WithWorkerState converts the Pool to a WorkerPool, using init to create the state of each worker and cleanup,
+// which can be nil, to clean it up.
+//
+// Each worker calls init for itself, so init may run concurrently on several workers.
+// The Pool must not have been used to run jobs before the conversion.
+// If init panics, the job that needed the state fails with a *PanicError, and the next job of the worker calls init again.
+// If cleanup panics, the worker still exits, and the panic is re-raised by Wait() or Collect() like the panic of a job.
+func WithWorkerState[S any](p *Pool, init func() S, cleanup func(S)) *WorkerPool[S] {
+	p.stateInit = func() any { return init() }
+	if cleanup != nil {
+		p.stateCleanup = func(s any) { cleanup(stateOf[S](s)) }
+	}
+
+	return &WorkerPool[S]{pool: p}
+}
+
+// stateOf converts the state of a worker back to S. A nil state of an interface type S is stored as a nil interface,
+// which a plain type assertion would panic on, so it is converted to the zero value of S instead.
+func stateOf[S any](state any) S {
+	s, _ := state.(S)
+	return s
+}
+
+// Go submits a job to the worker pool, which receives the state of the worker that runs it.
+//
+// If a job is submitted after Wait() has been called, it will be dropped silently.
+// If the job panics, the worker recovers and keeps running with the same state; the panic is re-raised by Wait() or Collect().
+func (p *WorkerPool[S]) Go(job func(S)) {
+	_ = p.pool.submit(context.Background(), &task{stateful: func(state func() any) { job(stateOf[S](state())) }, weight: 1}, true)
+}
+
+// TryGo attempts to submit a job to the worker pool without blocking.
+//
+// If a job is submitted after Wait() has been called, or all workers are busy and the queue is full, it will be dropped and false is returned.
+// Otherwise, true is returned.
+func (p *WorkerPool[S]) TryGo(job func(S)) bool {
+	return p.pool.submit(context.Background(), &task{stateful: func(state func() any) { job(stateOf[S](state())) }, weight: 1}, false) == nil
+}
+
+// Collect blocks until all submitted jobs are finished.
+//
+// This does not prevent new jobs from being submitted after using Collect().
+// Collect() does not close the worker pool and stop the goroutine workers, so their states are kept.
+func (p *WorkerPool[S]) Collect() {
+	p.pool.Collect()
+}
+
+// Wait closes the job queue and blocks until all workers finish the jobs and clean up their states.
+//
+// After calling Wait(), the worker pool is considered closed; new jobs will be dropped.
+func (p *WorkerPool[S]) Wait() {
+	p.pool.Wait()
+}
+
+// WithErrors converts the WorkerPool to a WorkerErrorPool
+//
+// WorkerErrorPool accepts jobs that receive the state of their worker and can return errors.
+func (p *WorkerPool[S]) WithErrors(opts ...Option) *WorkerErrorPool[S] {
+	return &WorkerErrorPool[S]{
+		errorPool: p.pool.WithErrors(opts...),
+	}
+}
@@ -0,0 +1,191 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kiriyms/conpats/pool"
+)
+
+type workerState struct {
+	id   int64
+	jobs int
+}
+
+func TestWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates state once per worker and cleans it up", func(t *testing.T) {
+		t.Parallel()
+
+		var created, cleaned atomic.Int64
+		var mu sync.Mutex
+		jobs := 0
+
+		p := pool.WithWorkerState(pool.New(3), func() *workerState {
+			return &workerState{id: created.Add(1)}
+		}, func(s *workerState) {
+			cleaned.Add(1)
+			mu.Lock()
+			jobs += s.jobs
+			mu.Unlock()
+		})
+
+		for i := 0; i < 50; i++ {
+			p.Go(func(s *workerState) {
+				s.jobs++
+			})
+		}
+		p.Wait()
+
+		if created.Load() < 1 || created.Load() > 3 {
+			t.Errorf("Expected between 1 and 3 states, got %d", created.Load())
+		}
+		if cleaned.Load() != created.Load() {
+			t.Errorf("Expected %d states to be cleaned up, got %d", created.Load(), cleaned.Load())
+		}
+		if jobs != 50 {
+			t.Errorf("Expected 50 jobs counted across states, got %d", jobs)
+		}
+	})
+
+	t.Run("keeps state across Collect", func(t *testing.T) {
+		t.Parallel()
+
+		var created atomic.Int64
+		p := pool.WithWorkerState(pool.New(1), func() int {
+			return int(created.Add(1))
+		}, nil)
+
+		p.Go(func(int) {})
+		p.Collect()
+		p.Go(func(int) {})
+		p.Wait()
+
+		if created.Load() != 1 {
+			t.Errorf("Expected 1 state, got %d", created.Load())
+		}
+	})
+
+	t.Run("passes nil states of interface types", func(t *testing.T) {
+		t.Parallel()
+
+		var cleaned atomic.Int64
+		p := pool.WithWorkerState(pool.New(1), func() io.Writer {
+			return nil
+		}, func(w io.Writer) {
+			if w != nil {
+				t.Errorf("Expected a nil state to clean up, got %v", w)
+			}
+			cleaned.Add(1)
+		})
+
+		var ran atomic.Int64
+		p.Go(func(w io.Writer) {
+			if w != nil {
+				t.Errorf("Expected a nil state, got %v", w)
+			}
+			ran.Add(1)
+		})
+		p.Collect()
+
+		ep := p.WithErrors()
+		ep.Go(func(w io.Writer) error {
+			ran.Add(1)
+			return nil
+		})
+		if errs := ep.Wait(); errs != nil {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+
+		if ran.Load() != 2 {
+			t.Errorf("Expected 2 jobs to run, got %d", ran.Load())
+		}
+		if cleaned.Load() != 1 {
+			t.Errorf("Expected the state to be cleaned up once, got %d", cleaned.Load())
+		}
+	})
+
+	t.Run("re-raises cleanup panics from Wait", func(t *testing.T) {
+		t.Parallel()
+
+		p := pool.WithWorkerState(pool.New(2), func() int {
+			return 0
+		}, func(int) {
+			panic("cleanup failed")
+		})
+
+		for i := 0; i < 10; i++ {
+			p.Go(func(int) {})
+		}
+
+		defer func() {
+			r := recover()
+			if _, ok := r.(*pool.PanicError); !ok {
+				t.Errorf("Expected *pool.PanicError from cleanup, got %v", r)
+			}
+		}()
+		p.Wait()
+		t.Errorf("Expected Wait to re-raise the cleanup panic")
+	})
+
+	t.Run("collects errors and init panics", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int64
+		p := pool.WithWorkerState(pool.New(1), func() int {
+			if calls.Add(1) == 1 {
+				panic("init failed")
+			}
+			return 42
+		}, nil).WithErrors()
+		errTest := errors.New("test")
+
+		p.Go(func(s int) error { return nil })
+		p.Go(func(s int) error {
+			if s != 42 {
+				t.Errorf("Expected state 42, got %d", s)
+			}
+			return errTest
+		})
+
+		errs := p.Wait()
+		if len(errs) != 2 {
+			t.Fatalf("Expected 2 errors, got %d", len(errs))
+		}
+		var pe *pool.PanicError
+		if !errors.As(errs[0], &pe) {
+			t.Errorf("Expected *pool.PanicError from init, got %v", errs[0])
+		}
+		if !errors.Is(errs[1], errTest) {
+			t.Errorf("Expected error %v, got %v", errTest, errs[1])
+		}
+	})
+
+	t.Run("passes context and state to ContextPool jobs", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p := pool.WithWorkerState(pool.New(2), func() string {
+			return "conn"
+		}, nil).WithErrors().WithContext(ctx, pool.WithCancelOnErr())
+		errTest := errors.New("test")
+
+		p.Go(func(ctx context.Context, s string) error {
+			if s != "conn" {
+				t.Errorf("Expected state conn, got %s", s)
+			}
+			return errTest
+		})
+
+		if err := p.WaitErr(); !errors.Is(err, errTest) {
+			t.Errorf("Expected error %v, got %v", errTest, err)
+		}
+	})
+}
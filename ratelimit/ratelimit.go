@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket rate limiter. The bucket holds up to burst tokens and is refilled at rate tokens per second;
+// every event takes one token.
+//
+// A new limiter must be created using New(). A Limiter is safe for concurrent use, and can be shared by several pools and pipes
+// to enforce a common limit.
+type Limiter struct {
+	rate  float64
+	burst int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// New creates a new Limiter allowing rate events per second on average, and bursts of up to burst events. The bucket starts full.
+//
+// A burst of 0 or less is treated as 1. A rate of 0 or less means that the limiter never refills, so only the first burst events are allowed.
+func New(rate float64, burst int) *Limiter {
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &Limiter{
+		rate:   max(rate, 0),
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Every converts an interval between events to a rate, which can be passed to New().
+func Every(interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(interval)
+}
+
+// Allow takes a token if one is available right away, and reports whether it did.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+
+	l.tokens--
+	return true
+}
+
+// Wait blocks until a token is available and takes it, or until ctx is done.
+//
+// If ctx is done first, the token is given back and ctx.Err() is returned.
+// If the limiter cannot provide a token before the deadline of ctx, Wait returns context.DeadlineExceeded right away.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.rate == 0 {
+		l.tokens++
+		l.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		l.tokens++
+		l.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.refill(time.Now())
+		l.tokens = min(l.tokens+1, float64(l.burst))
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// refill adds the tokens accumulated since the last refill. It must be called with l.mu held.
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, float64(l.burst))
+		l.last = now
+	}
+}
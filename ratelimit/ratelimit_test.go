@@ -0,0 +1,101 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kiriyms/conpats/ratelimit"
+)
+
+func TestLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("allows a burst then refills", func(t *testing.T) {
+		t.Parallel()
+
+		l := ratelimit.New(100, 3)
+		for i := 0; i < 3; i++ {
+			if !l.Allow() {
+				t.Fatalf("Expected event %d of the burst to be allowed", i)
+			}
+		}
+		if l.Allow() {
+			t.Error("Expected event after the burst to be denied")
+		}
+
+		time.Sleep(15 * time.Millisecond)
+		if !l.Allow() {
+			t.Error("Expected event to be allowed after refill")
+		}
+	})
+
+	t.Run("Wait paces events", func(t *testing.T) {
+		t.Parallel()
+
+		l := ratelimit.New(ratelimit.Every(5*time.Millisecond), 1)
+		start := time.Now()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := l.Wait(context.Background()); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("Expected 5 events to take at least 20ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("Wait returns on cancellation and gives the token back", func(t *testing.T) {
+		t.Parallel()
+
+		l := ratelimit.New(10, 1)
+		if !l.Allow() {
+			t.Fatal("Expected first event to be allowed")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- l.Wait(ctx)
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		if !l.Allow() {
+			t.Error("Expected the abandoned token to be available again")
+		}
+	})
+
+	t.Run("Wait fails fast past the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		l := ratelimit.New(1, 1)
+		l.Allow()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Millisecond {
+			t.Errorf("Expected Wait to return right away, took %v", elapsed)
+		}
+	})
+}